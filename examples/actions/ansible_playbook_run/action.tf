@@ -7,6 +7,7 @@ action "ansible_playbook_run" "ansible" {
     extra_vars = {
       var_a = "Some variable"
       var_b = "Another variable"
+      ports = [80, 443]
     }
   }
 }
@@ -1,6 +1,9 @@
 data "ansible_inventory" "myinventory" {
   group {
     name = "webservers"
+    vars_json = jsonencode({
+      http_ports = [80, 443]
+    })
 
     host {
       name                     = aws_instance.web.public_ip
@@ -17,12 +20,20 @@ data "ansible_inventory" "myinventory" {
       name                     = aws_instance.primary_db.public_ip
       ansible_user             = "root"
       ansible_private_key_file = local_file.private_key.filename
+
+      vars = {
+        node_role = "primary"
+      }
     }
 
     host {
       name                     = aws_instance.fallback_db.public_ip
       ansible_user             = "root"
       ansible_private_key_file = local_file.private_key.filename
+
+      vars = {
+        node_role = "fallback"
+      }
     }
   }
 }
@@ -0,0 +1,17 @@
+data "ansible_inventory_file" "site" {
+  path = "${path.module}/inventory.ini"
+}
+
+# One DNS record per web server of the inventory.
+resource "aws_route53_record" "web" {
+  for_each = {
+    for host in data.ansible_inventory_file.site.hosts : host.name => host
+    if contains(host.groups, "webservers")
+  }
+
+  zone_id = aws_route53_zone.main.zone_id
+  name    = each.key
+  type    = "A"
+  ttl     = 300
+  records = [each.value.vars["ansible_host"]]
+}
@@ -0,0 +1,10 @@
+data "ansible_inventory_list" "aws" {
+  inventory_files = ["${path.module}/inventory/aws_ec2.yml"]
+  limit           = "tag_Role_web"
+}
+
+output "web_addresses" {
+  value = {
+    for host in data.ansible_inventory_list.aws.hosts : host.name => host.vars["ansible_host"]
+  }
+}
@@ -0,0 +1,13 @@
+ephemeral "ansible_vault" "secrets" {
+  vault_file          = "vault.yml"
+  vault_password_file = "/path/to/file"
+}
+
+resource "ansible_playbook" "playbook" {
+  playbook = "playbook.yml"
+  name     = "host-1.example.com"
+
+  sensitive_extra_vars = {
+    secrets = ephemeral.ansible_vault.secrets.yaml
+  }
+}
@@ -7,6 +7,24 @@ terraform {
   }
 }
 
+# Settings shared by every ansible_playbook resource and ansible_playbook_run action.
+# Values set on a resource or action take precedence.
+provider "ansible" {
+  ansible_playbook_binary = "/usr/local/bin/ansible-playbook"
+  private_key_file        = "~/.ssh/id_ed25519"
+  user                    = "ubuntu"
+  verbosity               = 1
+
+  extra_vars = {
+    environment = "production"
+  }
+
+  # Variables which are not strings
+  extra_vars_json = jsonencode({
+    http_ports = [80, 443]
+  })
+}
+
 
 resource "ansible_vault" "secrets" {
   vault_file          = "vault.yml"
@@ -4,4 +4,7 @@ resource "ansible_group" "group" {
   variables = {
     hello = "from group!"
   }
+  variables_json = jsonencode({
+    http_ports = [80, 443]
+  })
 }
@@ -0,0 +1,2 @@
+# The ID is the host name and the playbook path, separated by a comma.
+terraform import ansible_playbook.webserver "host-1.example.com,webserver.yml"
@@ -6,5 +6,6 @@ resource "ansible_playbook" "playbook" {
   extra_vars = {
     var_a = "Some variable"
     var_b = "Another variable"
+    ports = [80, 443]
   }
 }
@@ -0,0 +1,13 @@
+resource "random_password" "database" {
+  length = 32
+}
+
+resource "ansible_vault_encrypted" "secrets" {
+  vault_file          = "group_vars/all/vault.yml"
+  vault_password_file = "/path/to/file"
+  vault_id            = "prod"
+
+  data = {
+    database_password = random_password.database.result
+  }
+}
@@ -24,13 +24,39 @@ var (
 	ErrClosingClosedWriter   = errors.New("closing closed writer")
 )
 
-var _ action.ActionWithValidateConfig = (*runPlaybookRunAction)(nil)
+var (
+	_ action.ActionWithValidateConfig = (*runPlaybookRunAction)(nil)
+	_ action.ActionWithConfigure      = (*runPlaybookRunAction)(nil)
+)
 
 func NewRunPlaybookRunAction() action.Action {
 	return &runPlaybookRunAction{}
 }
 
-type runPlaybookRunAction struct{}
+type runPlaybookRunAction struct {
+	providerConfig *providerutils.ProviderConfig
+}
+
+func (a *runPlaybookRunAction) Configure(
+	ctx context.Context,
+	req action.ConfigureRequest,
+	resp *action.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerConfig, ok := req.ProviderData.(*providerutils.ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *providerutils.ProviderConfig, got %T", req.ProviderData),
+		)
+		return
+	}
+
+	a.providerConfig = providerConfig
+}
 
 func (a *runPlaybookRunAction) Metadata(
 	ctx context.Context,
@@ -94,13 +120,27 @@ func (a *runPlaybookRunAction) Schema(ctx context.Context, req action.SchemaRequ
 				ElementType: types.StringType,
 				Required:    false,
 				Optional:    true,
-				Description: "The vault identities to use",
+				Description: "The vault identities to use. " +
+					"Defaults to the provider's `vault_id`, combined with the vault password file.",
 			},
 
 			"vault_password_file": schema.StringAttribute{
 				Required:    false,
 				Optional:    true,
-				Description: "The vault password file to use",
+				Description: "The vault password file to use. Defaults to the provider's `vault_password_file`.",
+			},
+
+			"vault_password": schema.StringAttribute{
+				Required:    false,
+				Optional:    true,
+				Description: "The vault password, instead of a vault password file. Action settings aren't stored in the state.",
+			},
+
+			"vault_password_command": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Description: "Command printing the vault password, and its arguments, instead of a vault password file.",
 			},
 
 			"check_mode": schema.BoolAttribute{
@@ -122,18 +162,19 @@ func (a *runPlaybookRunAction) Schema(ctx context.Context, req action.SchemaRequ
 				Description: "Prepend path(s) to module library",
 			},
 
-			"extra_vars": schema.MapAttribute{
-				ElementType: types.StringType,
-				Required:    false,
-				Optional:    true,
-				Description: "Extra variables to pass to the playbook",
+			"extra_vars": schema.DynamicAttribute{
+				Required: false,
+				Optional: true,
+				Description: "Extra variables to pass to the playbook, merged on top of the provider's `extra_vars`. " +
+					"Values can be strings, numbers, booleans, lists or objects, and are passed with their types, " +
+					"in a temporary JSON file.",
 			},
 
 			"extra_vars_files": schema.ListAttribute{
 				ElementType: types.StringType,
 				Required:    false,
 				Optional:    true,
-				Description: "List of variable files with extra variables",
+				Description: "List of variable files with extra variables. 'extra_vars' take precedence over them.",
 			},
 
 			"forks": schema.Int64Attribute{
@@ -142,6 +183,12 @@ func (a *runPlaybookRunAction) Schema(ctx context.Context, req action.SchemaRequ
 				Description: "Number of parallel forks to use",
 			},
 
+			"hosts": schema.DynamicAttribute{
+				Required:    false,
+				Optional:    true,
+				Description: inventoryHostsDescription,
+			},
+
 			"inventories": schema.ListAttribute{
 				ElementType: types.StringType,
 				Required:    false,
@@ -153,7 +200,15 @@ func (a *runPlaybookRunAction) Schema(ctx context.Context, req action.SchemaRequ
 				ElementType: types.StringType,
 				Required:    false,
 				Optional:    true,
-				Description: "Specify inventory host path or comma separated host list",
+				Description: "Specify inventory host path or comma separated host list. " +
+					"Defaults to the provider's `inventory_files`, which are used along with `inventories`, `hosts` " +
+					"and `inventory_groups`. Set it to an empty list to not use them.",
+			},
+
+			"inventory_groups": schema.DynamicAttribute{
+				Required:    false,
+				Optional:    true,
+				Description: inventoryGroupsDescription,
 			},
 
 			"limit": schema.StringAttribute{
@@ -172,13 +227,13 @@ func (a *runPlaybookRunAction) Schema(ctx context.Context, req action.SchemaRequ
 			"verbosity": schema.Int32Attribute{
 				Required:    false,
 				Optional:    true,
-				Description: "Verbosity level",
+				Description: "Verbosity level. Defaults to the provider's `verbosity` when it isn't set, 0 overrides it.",
 			},
 
 			"private_key_file": schema.StringAttribute{
 				Required:    false,
 				Optional:    true,
-				Description: "Path to private key file",
+				Description: "Path to private key file. Defaults to the provider's `private_key_file`.",
 			},
 
 			"scp_extra_args": schema.StringAttribute{
@@ -220,7 +275,7 @@ func (a *runPlaybookRunAction) Schema(ctx context.Context, req action.SchemaRequ
 			"user": schema.StringAttribute{
 				Required:    false,
 				Optional:    true,
-				Description: "Connect as this user (default=None)",
+				Description: "Connect as this user (default=None). Defaults to the provider's `user`.",
 			},
 
 			"become_user": schema.StringAttribute{
@@ -252,46 +307,50 @@ func (a *runPlaybookRunAction) Schema(ctx context.Context, req action.SchemaRequ
 			"ansible_playbook_binary": schema.StringAttribute{
 				Required:    false,
 				Optional:    true,
-				Description: "Path to ansible-playbook executable (binary).",
+				Description: "Path to ansible-playbook executable (binary). Defaults to the provider's `ansible_playbook_binary`, or `ansible-playbook`.",
 			},
 		},
 	}
 }
 
 type runPlaybookActionModel struct {
-	Playbooks              types.List   `tfsdk:"playbooks"`
-	AnsiblePlaybookBinary  types.String `tfsdk:"ansible_playbook_binary"`
-	BecomePasswordFile     types.String `tfsdk:"become_password_file"`
-	ConnectionPasswordFile types.String `tfsdk:"connection_password_file"`
-	SkipTags               types.List   `tfsdk:"skip_tags"`
-	StartAtTask            types.String `tfsdk:"start_at_task"`
-	VaultIds               types.List   `tfsdk:"vault_ids"`
-	VaultPasswordFile      types.String `tfsdk:"vault_password_file"`
-	CheckMode              types.Bool   `tfsdk:"check_mode"`
-	DiffMode               types.Bool   `tfsdk:"diff_mode"`
-	ModulePaths            types.List   `tfsdk:"module_paths"`
-	ExtraVars              types.Map    `tfsdk:"extra_vars"`
-	ExtraVarsFiles         types.List   `tfsdk:"extra_vars_files"`
-	Forks                  types.Int64  `tfsdk:"forks"`
-	Inventories            types.List   `tfsdk:"inventories"`
-	InventoryFiles         types.List   `tfsdk:"inventory_files"`
-	Limit                  types.String `tfsdk:"limit"`
-	Tags                   types.List   `tfsdk:"tags"`
-	Verbosity              types.Int32  `tfsdk:"verbosity"`
-	Quiet                  types.Bool   `tfsdk:"quiet"`
-	PrivateKeyFile         types.String `tfsdk:"private_key_file"`
-	ScpExtraArgs           types.String `tfsdk:"scp_extra_args"`
-	SftpExtraArgs          types.String `tfsdk:"sftp_extra_args"`
-	SshCommonArgs          types.String `tfsdk:"ssh_common_args"`
-	SshExtraArgs           types.String `tfsdk:"ssh_extra_args"`
-	Timeout                types.Int32  `tfsdk:"timeout"`
-	ConnectionType         types.String `tfsdk:"connection_type"`
-	User                   types.String `tfsdk:"user"`
-	BecomeUser             types.String `tfsdk:"become_user"`
-	BecomeMethod           types.String `tfsdk:"become_method"`
-	Become                 types.Bool   `tfsdk:"become"`
-	FlushCache             types.Bool   `tfsdk:"flush_cache"`
-	ForceHandlers          types.Bool   `tfsdk:"force_handlers"`
+	Playbooks              types.List    `tfsdk:"playbooks"`
+	AnsiblePlaybookBinary  types.String  `tfsdk:"ansible_playbook_binary"`
+	BecomePasswordFile     types.String  `tfsdk:"become_password_file"`
+	ConnectionPasswordFile types.String  `tfsdk:"connection_password_file"`
+	SkipTags               types.List    `tfsdk:"skip_tags"`
+	StartAtTask            types.String  `tfsdk:"start_at_task"`
+	VaultIds               types.List    `tfsdk:"vault_ids"`
+	VaultPasswordFile      types.String  `tfsdk:"vault_password_file"`
+	VaultPassword          types.String  `tfsdk:"vault_password"`
+	VaultPasswordCommand   types.List    `tfsdk:"vault_password_command"`
+	CheckMode              types.Bool    `tfsdk:"check_mode"`
+	DiffMode               types.Bool    `tfsdk:"diff_mode"`
+	ModulePaths            types.List    `tfsdk:"module_paths"`
+	ExtraVars              types.Dynamic `tfsdk:"extra_vars"`
+	ExtraVarsFiles         types.List    `tfsdk:"extra_vars_files"`
+	Forks                  types.Int64   `tfsdk:"forks"`
+	Hosts                  types.Dynamic `tfsdk:"hosts"`
+	Inventories            types.List    `tfsdk:"inventories"`
+	InventoryFiles         types.List    `tfsdk:"inventory_files"`
+	InventoryGroups        types.Dynamic `tfsdk:"inventory_groups"`
+	Limit                  types.String  `tfsdk:"limit"`
+	Tags                   types.List    `tfsdk:"tags"`
+	Verbosity              types.Int32   `tfsdk:"verbosity"`
+	Quiet                  types.Bool    `tfsdk:"quiet"`
+	PrivateKeyFile         types.String  `tfsdk:"private_key_file"`
+	ScpExtraArgs           types.String  `tfsdk:"scp_extra_args"`
+	SftpExtraArgs          types.String  `tfsdk:"sftp_extra_args"`
+	SshCommonArgs          types.String  `tfsdk:"ssh_common_args"`
+	SshExtraArgs           types.String  `tfsdk:"ssh_extra_args"`
+	Timeout                types.Int32   `tfsdk:"timeout"`
+	ConnectionType         types.String  `tfsdk:"connection_type"`
+	User                   types.String  `tfsdk:"user"`
+	BecomeUser             types.String  `tfsdk:"become_user"`
+	BecomeMethod           types.String  `tfsdk:"become_method"`
+	Become                 types.Bool    `tfsdk:"become"`
+	FlushCache             types.Bool    `tfsdk:"flush_cache"`
+	ForceHandlers          types.Bool    `tfsdk:"force_handlers"`
 }
 
 func (a *runPlaybookRunAction) ValidateConfig(
@@ -346,17 +405,27 @@ func (a *runPlaybookRunAction) ValidateConfig(
 		}
 	}
 
-	if !config.VaultIds.IsUnknown() && !config.VaultPasswordFile.IsUnknown() {
-		var vaultFiles []types.String
-		resp.Diagnostics.Append(config.VaultIds.ElementsAs(ctx, &vaultFiles, false)...)
-		// We can already do some validations here during plan
-		if len(vaultFiles) != 0 && config.VaultPasswordFile.ValueString() == "" {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("vault_password_file"),
-				"vault_password_file is not found",
-				"Can not access vault_files without passing the vault_password_file",
-			)
-		}
+	_, err := decodeVars(ctx, config.ExtraVars)
+	if err != nil && !errors.Is(err, errUnknownValue) {
+		resp.Diagnostics.AddAttributeError(path.Root("extra_vars"), "Invalid extra_vars", err.Error())
+	}
+
+	_, err = decodeInventoryHosts(ctx, config.Hosts)
+	if err != nil && !errors.Is(err, errUnknownValue) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hosts"),
+			"Invalid hosts",
+			"Expected a list of objects with a 'name', and optionally 'groups' and 'vars': "+err.Error(),
+		)
+	}
+
+	_, err = decodeInventoryGroups(ctx, config.InventoryGroups)
+	if err != nil && !errors.Is(err, errUnknownValue) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("inventory_groups"),
+			"Invalid inventory groups",
+			"Expected a list of objects with a 'name', and optionally 'children' and 'vars': "+err.Error(),
+		)
 	}
 
 	if config.BecomePasswordFile.ValueString() != "" {
@@ -437,19 +506,10 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 		return
 	}
 
-	ansiblePlaybookBinary := "ansible-playbook"
-	if config.AnsiblePlaybookBinary.ValueString() != "" {
-		ansiblePlaybookBinary = config.AnsiblePlaybookBinary.ValueString()
-	}
+	ansiblePlaybookBinary := a.providerConfig.PlaybookBinary(config.AnsiblePlaybookBinary.ValueString())
 
-	// Validate ansible-playbook binary
-	_, validateBinPath := exec.LookPath(ansiblePlaybookBinary)
-	if validateBinPath != nil {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("ansible_playbook_binary"),
-			"ansible_playbook_binary is not found",
-			fmt.Sprintf("The ansible-playbook binary is not found: %s", validateBinPath),
-		)
+	resp.Diagnostics.Append(lookupBinary(ansiblePlaybookBinary, "ansible_playbook_binary", "ansible-playbook")...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 	/********************
@@ -474,7 +534,10 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 
 	flags := []string{}
 
-	verbosityLevel := int(config.Verbosity.ValueInt32())
+	// The passwords of the run are masked in the logs, progress messages and diagnostics.
+	redactor := providerutils.NewRedactor()
+
+	verbosityLevel := a.providerConfig.PlaybookVerbosity(intPointer(config.Verbosity.ValueInt32Pointer()))
 	verbose := providerutils.CreateVerboseSwitch(verbosityLevel)
 	if verbose != "" {
 		flags = append(flags, verbose)
@@ -483,11 +546,13 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 	becomePasswordFile := config.BecomePasswordFile.ValueString()
 	if becomePasswordFile != "" {
 		flags = append(flags, "--become-password-file", becomePasswordFile)
+		redactor.AddSecretFile(becomePasswordFile)
 	}
 
 	connectionPasswordFile := config.ConnectionPasswordFile.ValueString()
 	if connectionPasswordFile != "" {
 		flags = append(flags, "--connection-password-file", connectionPasswordFile)
+		redactor.AddSecretFile(connectionPasswordFile)
 	}
 
 	if config.ForceHandlers.ValueBool() {
@@ -519,14 +584,62 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 		return
 	}
 
+	vaultPassword := providerutils.VaultPassword{Password: config.VaultPassword.ValueString()}
+
+	resp.Diagnostics.Append(config.VaultPasswordCommand.ElementsAs(ctx, &vaultPassword.Command, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !vaultPassword.IsSet() {
+		vaultPassword.File = a.providerConfig.PlaybookVaultPasswordFile(config.VaultPasswordFile.ValueStringPointer())
+	}
+
+	redactor.AddSecrets(vaultPassword.Password)
+
+	vaultPasswordFile := ""
+
+	// A vault password which isn't read from a file is written to a temporary password file.
+	if vaultPassword.IsSet() {
+		passwordFile, removePasswordFile, err := vaultPassword.PasswordFile(ctx, a.providerConfig.PlaybookVaultID(nil))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read the vault password", redactor.Redact(err.Error()))
+			return
+		}
+
+		defer func() {
+			err := removePasswordFile()
+			if err != nil {
+				tflog.Warn(ctx, err.Error())
+			}
+		}()
+
+		vaultPasswordFile = passwordFile
+	}
+
+	redactor.AddSecretFile(vaultPasswordFile)
+
 	if len(vaultIds) > 0 {
+		if vaultPasswordFile == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("vault_password_file"),
+				"vault_password_file is not found",
+				"Can not access vault_files without passing the vault_password_file, vault_password or vault_password_command",
+			)
+			return
+		}
+
 		for _, vaultId := range vaultIds {
 			flags = append(flags, "--vault-id", vaultId.ValueString())
 		}
 	}
 
-	vaultPasswordFile := config.VaultPasswordFile.ValueString()
-	if vaultPasswordFile != "" {
+	defaultVaultID := a.providerConfig.PlaybookVaultID(nil)
+	switch {
+	case len(vaultIds) == 0 && defaultVaultID != "" && vaultPasswordFile != "":
+		// The provider's vault_id is bound to the password file, like in the ansible_playbook resource.
+		flags = append(flags, "--vault-id", defaultVaultID+"@"+vaultPasswordFile)
+	case vaultPasswordFile != "":
 		flags = append(flags, "--vault-password-file", vaultPasswordFile)
 	}
 
@@ -548,14 +661,23 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 		flags = append(flags, "--module-path", modulePath.ValueString())
 	}
 
-	var extraVars map[string]string
-	resp.Diagnostics.Append(config.ExtraVars.ElementsAs(ctx, &extraVars, false)...)
-	if resp.Diagnostics.HasError() {
+	extraVars, err := decodeVars(ctx, config.ExtraVars)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("extra_vars"), "Invalid extra_vars", err.Error())
+		return
+	}
+
+	extraVars = a.providerConfig.PlaybookExtraVars(extraVars)
+	redactor.AddPasswordVars(extraVars)
+
+	extraVarsFile, err := providerutils.ExtraVarsFile(extraVars)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("extra_vars"), "Failed to write the extra vars", err.Error())
 		return
 	}
 
-	for key, value := range extraVars {
-		flags = append(flags, "-e", fmt.Sprintf("%s=%s", key, value))
+	if extraVarsFile != "" {
+		defer os.Remove(extraVarsFile)
 	}
 
 	var extraVarsFiles []types.String
@@ -564,8 +686,13 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 		return
 	}
 
-	for _, extraVarsFile := range extraVarsFiles {
-		flags = append(flags, "-e", "@"+extraVarsFile.ValueString())
+	for _, varsFile := range extraVarsFiles {
+		flags = append(flags, "-e", "@"+varsFile.ValueString())
+	}
+
+	// The extra vars come last, so they take precedence over the var files, like with ansible_playbook.
+	if extraVarsFile != "" {
+		flags = append(flags, "-e", "@"+extraVarsFile)
 	}
 
 	forks := config.Forks.ValueInt64()
@@ -573,14 +700,14 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 		flags = append(flags, "--forks", strconv.FormatInt(forks, 10))
 	}
 
-	var inventoryFiles []types.String
-	resp.Diagnostics.Append(config.InventoryFiles.ElementsAs(ctx, &inventoryFiles, false)...)
+	inventoryFiles, diags := stringsPointer(ctx, config.InventoryFiles)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	for _, inventory := range inventoryFiles {
-		flags = append(flags, "--inventory", inventory.ValueString())
+	for _, inventory := range a.providerConfig.PlaybookInventoryFiles(inventoryFiles) {
+		flags = append(flags, "--inventory", inventory)
 	}
 
 	var inventories []types.String
@@ -588,13 +715,46 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	hosts, err := decodeInventoryHosts(ctx, config.Hosts)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("hosts"), "Invalid hosts", err.Error())
+		return
+	}
+
+	groups, err := decodeInventoryGroups(ctx, config.InventoryGroups)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("inventory_groups"), "Invalid inventory groups", err.Error())
+		return
+	}
+
+	for _, host := range hosts {
+		redactor.AddPasswordVars(host.Vars)
+	}
+
+	for _, group := range groups {
+		redactor.AddPasswordVars(group.Vars)
+	}
+
+	if len(hosts) > 0 || len(groups) > 0 {
+		tmpInventoryFile, err := providerutils.BuildInventory("action_ansible_playbook_run_inventory_*.json", hosts, groups)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to create temporary inventory file", err.Error())
+			return
+		}
+		defer os.Remove(tmpInventoryFile)
+
+		flags = append(flags, "--inventory", tmpInventoryFile)
+	}
 	for idx, inventory := range inventories {
-		tflog.Warn(ctx, fmt.Sprintf("inventory --> %#v", inventory))
+		redactor.AddInventoryPasswords(inventory.ValueString())
+		tflog.Debug(ctx, "inventory --> "+redactor.Redact(inventory.ValueString()))
 		if !isJSON(inventory.ValueString()) {
 			resp.Diagnostics.AddAttributeError(
 				path.Root("inventories").AtListIndex(idx),
 				"Invalid JSON",
-				fmt.Sprintf("Expected the inventory to contain valid JSON, got %q", inventory.ValueString()),
+				// The inventory can hold passwords, so it isn't shown.
+				"Expected the inventory to contain valid JSON",
 			)
 			return
 		}
@@ -638,7 +798,7 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 		flags = append(flags, "--tags", tag.ValueString())
 	}
 
-	privateKeyFile := config.PrivateKeyFile.ValueString()
+	privateKeyFile := a.providerConfig.PlaybookPrivateKeyFile(config.PrivateKeyFile.ValueStringPointer())
 	if privateKeyFile != "" {
 		flags = append(flags, "--private-key", privateKeyFile)
 	}
@@ -673,7 +833,7 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 		flags = append(flags, "--connection", connection)
 	}
 
-	user := config.User.ValueString()
+	user := a.providerConfig.PlaybookUser(config.User.ValueStringPointer())
 	if user != "" {
 		flags = append(flags, "--user", user)
 	}
@@ -695,36 +855,76 @@ func (a *runPlaybookRunAction) Invoke(ctx context.Context, req action.InvokeRequ
 	flags = append(flags, positionalArgs...)
 	args := flags
 
-	tflog.Info(ctx, fmt.Sprintf("Running Command <%s %s>", ansiblePlaybookBinary, strings.Join(args, " ")))
+	tflog.Info(ctx, fmt.Sprintf(
+		"Running Command <%s %s>",
+		ansiblePlaybookBinary,
+		strings.Join(redactor.RedactArgs(args), " "),
+	))
 
 	cmd := exec.CommandContext(ctx, ansiblePlaybookBinary, args...)
 
+	resultsCallback, err := providerutils.NewResultsCallback()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to set up the playbook results callback", err.Error())
+		return
+	}
+	defer func() {
+		err := resultsCallback.Cleanup()
+		if err != nil {
+			tflog.Warn(ctx, err.Error())
+		}
+	}()
+
+	cmd.Env = append(os.Environ(), resultsCallback.Env()...)
+
 	var stderr strings.Builder
 	cmd.Stderr = &stderr
-	cmd.Stdout = &TerraformUiWriter{
+	stdout := &TerraformUiWriter{
 		send: func(s string) {
 			if !config.Quiet.ValueBool() {
 				resp.SendProgress(action.InvokeProgressEvent{
-					Message: "ansible-playbook: " + s,
+					Message: "ansible-playbook: " + redactor.Redact(s),
 				})
 			}
 		},
 	}
+	cmd.Stdout = stdout
 
 	if !config.Quiet.ValueBool() {
 		resp.SendProgress(action.InvokeProgressEvent{
-			Message: "Running " + cmd.String(),
+			Message: "Running " + redactor.Redact(cmd.String()),
 		})
 	}
 
-	err := cmd.Run()
+	err = cmd.Run()
+
+	closeErr := stdout.Close()
+	if closeErr != nil {
+		tflog.Warn(ctx, closeErr.Error())
+	}
+
+	results, resultsErr := resultsCallback.Results()
+	if resultsErr != nil {
+		tflog.Warn(ctx, resultsErr.Error())
+	}
 
 	stderrStr := stderr.String()
 	if err != nil {
+		// Report exactly which tasks failed on which hosts when the results callback could tell.
+		if results != nil && len(results.Failures()) > 0 {
+			for _, failure := range results.Failures() {
+				resp.Diagnostics.AddError(
+					fmt.Sprintf("ansible-playbook task %s on host %q", failure.Status, failure.Host),
+					fmt.Sprintf("Task %q in play %q: %s", failure.Task, failure.Play, redactor.Redact(failure.Message)),
+				)
+			}
+			return
+		}
+
 		if len(stderrStr) > 0 {
 			resp.Diagnostics.AddError(
 				"ansible-playbook failed",
-				stderrStr,
+				redactor.Redact(stderrStr),
 			)
 			return
 		}
@@ -753,9 +953,11 @@ func (t *TerraformUiWriter) Write(data []byte) (int, error) {
 	now := time.Now()
 	shouldFlush := t.lastFlush.IsZero() || now.Sub(t.lastFlush) >= time.Second
 
-	if shouldFlush && len(t.buffer) > 0 {
-		t.send(t.buffer)
-		t.buffer = ""
+	// Only whole lines are sent, so that a secret split across writes is still masked.
+	lineEnd := strings.LastIndexByte(t.buffer, '\n')
+	if shouldFlush && lineEnd >= 0 {
+		t.send(t.buffer[:lineEnd+1])
+		t.buffer = t.buffer[lineEnd+1:]
 		t.lastFlush = now
 	}
 
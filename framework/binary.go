@@ -0,0 +1,26 @@
+package framework
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// lookupBinary checks that an Ansible CLI executable, e.g. ansible-playbook, can be found in the PATH
+// (or at the given path), and reports it on the attribute which sets it otherwise.
+func lookupBinary(binary string, attribute string, command string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	_, err := exec.LookPath(binary)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			attribute+" is not found",
+			fmt.Sprintf("The %s binary is not found: %s", command, err),
+		)
+	}
+
+	return diags
+}
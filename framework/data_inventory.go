@@ -3,8 +3,13 @@ package framework
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"maps"
+	"reflect"
+	"slices"
+	"strings"
 
+	"github.com/ansible/terraform-provider-ansible/providerutils"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -29,14 +34,19 @@ func (i *InventoryDataSource) Metadata(
 }
 
 type InventoryDataSourceModel struct {
-	Groups types.List   `tfsdk:"group"`
-	Json   types.String `tfsdk:"json"`
+	Groups   types.List   `tfsdk:"group"`
+	Json     types.String `tfsdk:"json"`
+	ListJson types.String `tfsdk:"list_json"`
+	Yaml     types.String `tfsdk:"yaml"`
+	Ini      types.String `tfsdk:"ini"`
 }
 
 type SharedGroupModel struct {
-	Name  types.String `tfsdk:"name"`
-	Vars  types.Map    `tfsdk:"vars"`
-	Hosts types.List   `tfsdk:"host"`
+	Name     types.String `tfsdk:"name"`
+	Children types.List   `tfsdk:"children"`
+	Vars     types.Map    `tfsdk:"vars"`
+	VarsJson types.String `tfsdk:"vars_json"`
+	Hosts    types.List   `tfsdk:"host"`
 }
 
 type NestedGroupModel struct {
@@ -52,12 +62,66 @@ type FinalGroupModel struct {
 // root plus two levels of nesting.
 const groupNestingLevel = 2
 
+// groupGraph holds the children of each group, from the nested blocks and the 'children' lists.
+type groupGraph map[string][]string
+
+// cycle returns a path of groups leading back to its first group, or nil if the groups form a DAG.
+func (g groupGraph) cycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[string]int{}
+
+	var visit func(path []string) []string
+	visit = func(path []string) []string {
+		name := path[len(path)-1]
+		switch state[name] {
+		case visiting:
+			return path[slices.Index(path, name):]
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		for _, child := range g[name] {
+			if cycle := visit(append(slices.Clip(path), child)); cycle != nil {
+				return cycle
+			}
+		}
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(g)) {
+		if cycle := visit([]string{name}); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
 func inventoryToJson(ctx context.Context, irm *InventoryDataSourceModel) ([]byte, diag.Diagnostics) {
-	jsonValue, diags := groupsToJson(ctx, irm.Groups, 0)
+	graph := groupGraph{}
+	jsonValue, diags := groupsToJson(ctx, irm.Groups, 0, graph)
 	if diags.HasError() {
 		return nil, diags
 	}
+	if cycle := graph.cycle(); cycle != nil {
+		diags.Append(diag.NewErrorDiagnostic(
+			"Cyclic group hierarchy",
+			fmt.Sprintf("Group %q is its own descendant: %s", cycle[0], strings.Join(cycle, " > ")),
+		))
+		return nil, diags
+	}
 	ret, err := json.Marshal(jsonValue)
+	if err == nil {
+		ret, err = providerutils.CanonicalJSON(ret)
+	}
 	if err != nil {
 		diags.Append(diag.NewErrorDiagnostic("Could not marshal inventory to JSON", err.Error()))
 		return nil, diags
@@ -65,7 +129,12 @@ func inventoryToJson(ctx context.Context, irm *InventoryDataSourceModel) ([]byte
 	return ret, nil
 }
 
-func groupsToJson(ctx context.Context, list types.List, level int) (map[string]json.RawMessage, diag.Diagnostics) {
+func groupsToJson(
+	ctx context.Context,
+	list types.List,
+	level int,
+	graph groupGraph,
+) (map[string]json.RawMessage, diag.Diagnostics) {
 	jsonValue := map[string]json.RawMessage{}
 	var diags diag.Diagnostics
 
@@ -77,7 +146,7 @@ func groupsToJson(ctx context.Context, list types.List, level int) (map[string]j
 			return nil, diags
 		}
 		for _, group := range nestedGroups {
-			groupJson, diags := nestedGroupToJson(ctx, group, level)
+			groupJson, diags := nestedGroupToJson(ctx, group, level, graph)
 			if diags.HasError() {
 				return nil, diags
 			}
@@ -101,6 +170,13 @@ func groupsToJson(ctx context.Context, list types.List, level int) (map[string]j
 			if diags.HasError() {
 				return nil, diags
 			}
+			childrenJson, diags := childrenToJson(ctx, group.SharedGroupModel, nil, graph)
+			if diags.HasError() {
+				return nil, diags
+			}
+			if childrenJson != nil {
+				groupJson["children"] = childrenJson
+			}
 			b, err := json.Marshal(groupJson)
 			if err != nil {
 				diags.Append(diag.NewErrorDiagnostic("Could not marshal group to JSON", err.Error()))
@@ -117,24 +193,67 @@ func nestedGroupToJson(
 	ctx context.Context,
 	group NestedGroupModel,
 	level int,
+	graph groupGraph,
 ) (map[string]json.RawMessage, diag.Diagnostics) {
 	jsonValue, diags := sharedGroupToJson(ctx, group.SharedGroupModel)
 	if diags.HasError() {
 		return nil, diags
 	}
 
-	groupsJson, diags := groupsToJson(ctx, group.Groups, level+1)
+	groupsJson, diags := groupsToJson(ctx, group.Groups, level+1, graph)
+	if diags.HasError() {
+		return nil, diags
+	}
+	childrenJson, diags := childrenToJson(ctx, group.SharedGroupModel, groupsJson, graph)
+	if diags.HasError() {
+		return nil, diags
+	}
+	jsonValue["children"] = childrenJson
+
+	return jsonValue, diags
+}
+
+// childrenToJson merges the groups referenced by name in 'children' with the nested groups, and records
+// them in the graph. The referenced groups are defined elsewhere in the inventory, with their own hosts,
+// vars and children, or are created empty, like in Ansible inventories.
+// nil is returned for a group without nested groups nor children.
+func childrenToJson(
+	ctx context.Context,
+	group SharedGroupModel,
+	nestedGroups map[string]json.RawMessage,
+	graph groupGraph,
+) (json.RawMessage, diag.Diagnostics) {
+	var children []string
+	diags := group.Children.ElementsAs(ctx, &children, false)
 	if diags.HasError() {
 		return nil, diags
 	}
-	b, err := json.Marshal(groupsJson)
+
+	name := group.Name.ValueString()
+	graph[name] = append(graph[name], slices.Sorted(maps.Keys(nestedGroups))...)
+
+	if nestedGroups == nil && len(children) == 0 {
+		return nil, diags
+	}
+
+	childrenJson := maps.Clone(nestedGroups)
+	if childrenJson == nil {
+		childrenJson = map[string]json.RawMessage{}
+	}
+	for _, child := range children {
+		if _, ok := childrenJson[child]; !ok {
+			childrenJson[child] = json.RawMessage("{}")
+		}
+		graph[name] = append(graph[name], child)
+	}
+
+	b, err := json.Marshal(childrenJson)
 	if err != nil {
 		diags.Append(diag.NewErrorDiagnostic("Could not marshal nested groups to JSON", err.Error()))
 		return nil, diags
 	}
-	jsonValue["children"] = b
 
-	return jsonValue, diags
+	return b, diags
 }
 
 func sharedGroupToJson(ctx context.Context, group SharedGroupModel) (map[string]json.RawMessage, diag.Diagnostics) {
@@ -148,7 +267,7 @@ func sharedGroupToJson(ctx context.Context, group SharedGroupModel) (map[string]
 
 	hostsJson := map[string]json.RawMessage{}
 	for _, host := range hosts {
-		hostJson, diags := hostToJson(&host)
+		hostJson, diags := hostToJson(ctx, &host)
 		if diags.HasError() {
 			return nil, diags
 		}
@@ -163,11 +282,11 @@ func sharedGroupToJson(ctx context.Context, group SharedGroupModel) (map[string]
 		jsonValue["hosts"] = b
 	}
 
-	var vars map[string]string
-	diags = group.Vars.ElementsAs(ctx, &vars, false)
+	vars, diags := inventoryVars(ctx, "group", group.Name.ValueString(), group.Vars, group.VarsJson)
 	if diags.HasError() {
 		return nil, diags
 	}
+
 	if len(vars) > 0 {
 		b, err := json.Marshal(vars)
 		if err != nil {
@@ -180,31 +299,114 @@ func sharedGroupToJson(ctx context.Context, group SharedGroupModel) (map[string]
 	return jsonValue, diags
 }
 
-func hostToJson(hostModel *HostModel) (json.RawMessage, diag.Diagnostics) {
-	diags := diag.Diagnostics{}
-	ret, err := json.Marshal(JsonHostModel{
-		AnsibleConnection:        hostModel.AnsibleConnection.ValueString(),
-		AnsibleHost:              hostModel.AnsibleHost.ValueString(),
-		AnsiblePort:              hostModel.AnsiblePort.ValueInt64(),
-		AnsibleUser:              hostModel.AnsibleUser.ValueString(),
-		AnsiblePassword:          hostModel.AnsiblePassword.ValueString(),
-		AnsiblePrivateKeyFile:    hostModel.AnsiblePrivateKeyFile.ValueString(),
-		AnsibleSSHCommonArgs:     hostModel.AnsibleSSHCommonArgs.ValueString(),
-		AnsibleSftpExtraArgs:     hostModel.AnsibleSftpExtraArgs.ValueString(),
-		AnsibleScpExtraArgs:      hostModel.AnsibleScpExtraArgs.ValueString(),
-		AnsibleSSHExtraArgs:      hostModel.AnsibleSSHExtraArgs.ValueString(),
-		AnsibleSSHPipelining:     hostModel.AnsibleSSHPipelining.ValueBool(),
-		AnsibleSSHExecutable:     hostModel.AnsibleSSHExecutable.ValueString(),
-		AnsibleBecome:            hostModel.AnsibleBecome.ValueBool(),
-		AnsibleBecomeMethod:      hostModel.AnsibleBecomeMethod.ValueString(),
-		AnsibleBecomeUser:        hostModel.AnsibleBecomeUser.ValueString(),
-		AnsibleBecomePassword:    hostModel.AnsibleBecomePassword.ValueString(),
-		AnsibleBecomeExe:         hostModel.AnsibleBecomeExe.ValueString(),
-		AnsibleBecomeFlags:       hostModel.AnsibleBecomeFlags.ValueString(),
-		AnsibleShellType:         hostModel.AnsibleShellType.ValueString(),
-		AnsiblePythonInterpreter: hostModel.AnsiblePythonInterpreter.ValueString(),
-		AnsibleShellExecutable:   hostModel.AnsibleShellExecutable.ValueString(),
-	})
+// inventoryVars merges the 'vars_json' of a group or a host on top of its 'vars'.
+func inventoryVars(
+	ctx context.Context,
+	kind string,
+	name string,
+	stringVars types.Map,
+	varsJson types.String,
+) (map[string]any, diag.Diagnostics) {
+	var elements map[string]string
+	diags := stringVars.ElementsAs(ctx, &elements, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	vars := map[string]any{}
+	for key, value := range elements {
+		vars[key] = value
+	}
+
+	if varsJson.ValueString() != "" {
+		decoder := json.NewDecoder(strings.NewReader(varsJson.ValueString()))
+		decoder.UseNumber()
+
+		var jsonVars map[string]any
+		err := decoder.Decode(&jsonVars)
+		if err != nil {
+			diags.Append(diag.NewErrorDiagnostic(
+				"Invalid vars_json",
+				fmt.Sprintf("Expected the vars_json of %s %q to be a JSON object: %s", kind, name, err),
+			))
+			return nil, diags
+		}
+
+		maps.Copy(vars, jsonVars)
+	}
+
+	return vars, diags
+}
+
+// hostParameterNames are the names of the behavioral parameters of HostModel (ansible_host, ansible_port, ...),
+// which can't be set as custom host variables.
+var hostParameterNames = func() []string {
+	hostType := reflect.TypeFor[HostModel]()
+
+	names := []string{}
+	for i := range hostType.NumField() {
+		if name := hostType.Field(i).Tag.Get("tfsdk"); strings.HasPrefix(name, "ansible_") {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}()
+
+// hostParameters returns the behavioral parameters set on a host, by their names. Unset parameters are left out,
+// and parameters set to their zero value, e.g. `ansible_become = false`, are kept.
+func hostParameters(hostModel *HostModel) map[string]any {
+	parameters := map[string]any{}
+
+	host := reflect.ValueOf(hostModel).Elem()
+	for i := range host.NumField() {
+		name := host.Type().Field(i).Tag.Get("tfsdk")
+		if !slices.Contains(hostParameterNames, name) {
+			continue
+		}
+
+		switch value := host.Field(i).Interface().(type) {
+		case types.String:
+			if !value.IsNull() && !value.IsUnknown() {
+				parameters[name] = value.ValueString()
+			}
+		case types.Int64:
+			if !value.IsNull() && !value.IsUnknown() {
+				parameters[name] = value.ValueInt64()
+			}
+		case types.Bool:
+			if !value.IsNull() && !value.IsUnknown() {
+				parameters[name] = value.ValueBool()
+			}
+		}
+	}
+
+	return parameters
+}
+
+func hostToJson(ctx context.Context, hostModel *HostModel) (json.RawMessage, diag.Diagnostics) {
+	vars, diags := inventoryVars(ctx, "host", hostModel.Name.ValueString(), hostModel.Vars, hostModel.VarsJson)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(vars)) {
+		if slices.Contains(hostParameterNames, key) {
+			diags.Append(diag.NewErrorDiagnostic(
+				"Conflicting host variable",
+				fmt.Sprintf("The variable %q of host %q is a behavioral parameter, set it with the %q attribute instead.",
+					key, hostModel.Name.ValueString(), key),
+			))
+		}
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	hostJson := hostParameters(hostModel)
+	maps.Copy(hostJson, vars)
+
+	ret, err := json.Marshal(hostJson)
 	if err != nil {
 		diags.Append(diag.NewErrorDiagnostic("Could not marshal host when marshalling inventory to JSON", err.Error()))
 		return nil, diags
@@ -236,34 +438,8 @@ type HostModel struct {
 	AnsibleShellType         types.String `tfsdk:"ansible_shell_type"`
 	AnsiblePythonInterpreter types.String `tfsdk:"ansible_python_interpreter"`
 	AnsibleShellExecutable   types.String `tfsdk:"ansible_shell_executable"`
-}
-
-// JsonHostModel represents the JSON structure for Ansible inventory hosts.
-// Note: JSON tags use snake_case to match Ansible's expected format.
-//
-//nolint:tagliatelle // Ansible requires snake_case JSON field names
-type JsonHostModel struct {
-	AnsibleConnection        string `json:"ansible_connection,omitempty"`
-	AnsibleHost              string `json:"ansible_host,omitempty"`
-	AnsiblePort              int64  `json:"ansible_port,omitempty"`
-	AnsibleUser              string `json:"ansible_user,omitempty"`
-	AnsiblePassword          string `json:"ansible_password,omitempty"`
-	AnsiblePrivateKeyFile    string `json:"ansible_private_key_file,omitempty"`
-	AnsibleSSHCommonArgs     string `json:"ansible_ssh_common_args,omitempty"`
-	AnsibleSftpExtraArgs     string `json:"ansible_sftp_extra_args,omitempty"`
-	AnsibleScpExtraArgs      string `json:"ansible_scp_extra_args,omitempty"`
-	AnsibleSSHExtraArgs      string `json:"ansible_ssh_extra_args,omitempty"`
-	AnsibleSSHPipelining     bool   `json:"ansible_ssh_pipelining,omitempty"`
-	AnsibleSSHExecutable     string `json:"ansible_ssh_executable,omitempty"`
-	AnsibleBecome            bool   `json:"ansible_become,omitempty"`
-	AnsibleBecomeMethod      string `json:"ansible_become_method,omitempty"`
-	AnsibleBecomeUser        string `json:"ansible_become_user,omitempty"`
-	AnsibleBecomePassword    string `json:"ansible_become_password,omitempty"`
-	AnsibleBecomeExe         string `json:"ansible_become_exe,omitempty"`
-	AnsibleBecomeFlags       string `json:"ansible_become_flags,omitempty"`
-	AnsibleShellType         string `json:"ansible_shell_type,omitempty"`
-	AnsiblePythonInterpreter string `json:"ansible_python_interpreter,omitempty"`
-	AnsibleShellExecutable   string `json:"ansible_shell_executable,omitempty"`
+	Vars                     types.Map    `tfsdk:"vars"`
+	VarsJson                 types.String `tfsdk:"vars_json"`
 }
 
 // Schema implements datasource.Resource.
@@ -397,6 +573,20 @@ func (i *InventoryDataSource) Schema(
 							Required:            false,
 							Optional:            true,
 						},
+						"vars": schema.MapAttribute{
+							MarkdownDescription: "Custom variables to be set for the host, e.g. `node_role`. " +
+								"The behavioral parameters above (`ansible_port`, `ansible_user`, ...) " +
+								"can't be set as variables, use their attributes instead.",
+							Required:    false,
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+						"vars_json": schema.StringAttribute{
+							MarkdownDescription: "Custom variables to be set for the host, as a JSON object, e.g. from `jsonencode()`, " +
+								"for values which are not strings (lists, objects, numbers, booleans). Merged on top of `vars`.",
+							Required: false,
+							Optional: true,
+						},
 					},
 				},
 			},
@@ -410,12 +600,26 @@ func (i *InventoryDataSource) Schema(
 						MarkdownDescription: "Name of the group.",
 						Required:            true,
 					},
+					"children": schema.ListAttribute{
+						MarkdownDescription: "Names of the child groups of the group, in addition to the nested `group` blocks. " +
+							"The child groups are defined by other `group` blocks, at any level, or are created empty. " +
+							"This allows hierarchies of any depth, and groups with several parents.",
+						Required:    false,
+						Optional:    true,
+						ElementType: types.StringType,
+					},
 					"vars": schema.MapAttribute{
 						MarkdownDescription: "Variables to be set for the group.",
 						Required:            false,
 						Optional:            true,
 						ElementType:         types.StringType,
 					},
+					"vars_json": schema.StringAttribute{
+						MarkdownDescription: "Variables to be set for the group, as a JSON object, e.g. from `jsonencode()`, " +
+							"for values which are not strings (lists, objects, numbers, booleans). Merged on top of `vars`.",
+						Required: false,
+						Optional: true,
+					},
 				},
 				Blocks: blocks,
 			},
@@ -433,14 +637,33 @@ func (i *InventoryDataSource) Schema(
 
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "This data source represents an ansible inventory. " +
-			"It has a json attribute containing the JSON representation of the inventory.",
+			"It has json, yaml and ini attributes containing the representations of the inventory in these formats, and a list_json attribute in the format of ansible-inventory --list.",
 		Attributes: map[string]schema.Attribute{
 			"json": schema.StringAttribute{
-				MarkdownDescription: "The JSON content of the inventory file.",
-				Required:            false,
-				Optional:            false,
+				MarkdownDescription: "The JSON content of the inventory file, with sorted keys, " +
+					"so that the content only changes with the inventory.",
+				Required:  false,
+				Optional:  false,
+				Computed:  true,
+				Sensitive: true, // Might contain sensitive info
+			},
+			"list_json": schema.StringAttribute{
+				MarkdownDescription: "The JSON content of the inventory in the format of `ansible-inventory --list`, " +
+					"which inventory scripts print: groups list their hosts and children, and the variables of the hosts " +
+					"are in `_meta.hostvars`.",
+				Computed:  true,
+				Sensitive: true,
+			},
+			"yaml": schema.StringAttribute{
+				MarkdownDescription: "The YAML content of the inventory file, with sorted keys.",
 				Computed:            true,
-				Sensitive:           true, // Might contain sensitive info
+				Sensitive:           true,
+			},
+			"ini": schema.StringAttribute{
+				MarkdownDescription: "The INI content of the inventory file, with sorted groups, hosts and variables. " +
+					"Nested groups are listed in `[group:children]` sections.",
+				Computed:  true,
+				Sensitive: true,
 			},
 		},
 		Blocks: map[string]schema.Block{
@@ -464,7 +687,28 @@ func (i *InventoryDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
+	yamlContent, err := providerutils.InventoryYAML(fileContent)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not convert the inventory to YAML", err.Error())
+		return
+	}
+
+	iniContent, err := providerutils.InventoryINI(fileContent)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not convert the inventory to INI", err.Error())
+		return
+	}
+
+	listContent, err := providerutils.InventoryListJSON(fileContent)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not convert the inventory to the ansible-inventory --list format", err.Error())
+		return
+	}
+
 	plan.Json = types.StringValue(string(fileContent))
+	plan.ListJson = types.StringValue(listContent)
+	plan.Yaml = types.StringValue(yamlContent)
+	plan.Ini = types.StringValue(iniContent)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -0,0 +1,247 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*InventoryFileDataSource)(nil)
+
+type InventoryFileDataSource struct{}
+
+func NewInventoryFileDataSource() datasource.DataSource {
+	return &InventoryFileDataSource{}
+}
+
+// Metadata implements datasource.Resource.
+func (i *InventoryFileDataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_inventory_file"
+}
+
+type InventoryFileDataSourceModel struct {
+	Path   types.String `tfsdk:"path"`
+	Format types.String `tfsdk:"format"`
+	Hosts  types.List   `tfsdk:"hosts"`
+	Groups types.List   `tfsdk:"groups"`
+}
+
+type inventoryFileHostModel struct {
+	Name     string            `tfsdk:"name"`
+	Groups   []string          `tfsdk:"groups"`
+	Vars     map[string]string `tfsdk:"vars"`
+	VarsJson string            `tfsdk:"vars_json"`
+}
+
+type inventoryFileGroupModel struct {
+	Name     string            `tfsdk:"name"`
+	Hosts    []string          `tfsdk:"hosts"`
+	Children []string          `tfsdk:"children"`
+	Vars     map[string]string `tfsdk:"vars"`
+	VarsJson string            `tfsdk:"vars_json"`
+}
+
+var inventoryFileHostAttrTypes = map[string]attr.Type{
+	"name":      types.StringType,
+	"groups":    types.ListType{ElemType: types.StringType},
+	"vars":      types.MapType{ElemType: types.StringType},
+	"vars_json": types.StringType,
+}
+
+var inventoryFileGroupAttrTypes = map[string]attr.Type{
+	"name":      types.StringType,
+	"hosts":     types.ListType{ElemType: types.StringType},
+	"children":  types.ListType{ElemType: types.StringType},
+	"vars":      types.MapType{ElemType: types.StringType},
+	"vars_json": types.StringType,
+}
+
+// Schema implements datasource.Resource.
+func (i *InventoryFileDataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This data source reads an existing Ansible inventory file, in the INI, YAML or JSON format, " +
+			"and exposes its hosts and groups, with their variables.",
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Path of the inventory file.",
+				Required:            true,
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "Format of the inventory file: `ini`, `yaml` or `json`. " +
+					"By default, files ending with `.yml` or `.yaml` are read as YAML, files ending with `.json` as JSON, " +
+					"and other files as INI.",
+				Optional: true,
+				Computed: true,
+			},
+			"hosts": schema.ListAttribute{
+				MarkdownDescription: "Hosts of the inventory, sorted by name. `groups` holds all the groups of the host, " +
+					"including the parents of its groups, like the `group_names` variable. `vars` holds the variables of the host " +
+					"merged with the ones of its groups, like Ansible does: values which are not strings are JSON encoded, " +
+					"and `vars_json` holds them all as a JSON object.",
+				Computed:    true,
+				ElementType: types.ObjectType{AttrTypes: inventoryFileHostAttrTypes},
+			},
+			"groups": schema.ListAttribute{
+				MarkdownDescription: "Groups of the inventory, sorted by name, with the hosts declared in the group, " +
+					"its child groups and its own variables, as in `hosts`.",
+				Computed:    true,
+				ElementType: types.ObjectType{AttrTypes: inventoryFileGroupAttrTypes},
+			},
+		},
+	}
+}
+
+// Read implements datasource.Resource.
+func (i *InventoryFileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config InventoryFileDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	inventoryPath := config.Path.ValueString()
+
+	format := config.Format.ValueString()
+	if format == "" {
+		format = providerutils.InventoryFileFormat(inventoryPath)
+	}
+
+	if !slices.Contains([]string{
+		providerutils.InventoryFormatINI, providerutils.InventoryFormatYAML, providerutils.InventoryFormatJSON,
+	}, format) {
+		resp.Diagnostics.AddAttributeError(path.Root("format"), "Invalid inventory format",
+			fmt.Sprintf("Expected one of %q, %q or %q, got %q.",
+				providerutils.InventoryFormatINI, providerutils.InventoryFormatYAML, providerutils.InventoryFormatJSON, format))
+		return
+	}
+
+	content, err := os.ReadFile(inventoryPath)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "Could not read the inventory file", err.Error())
+		return
+	}
+
+	inventory, err := providerutils.ParseInventory(content, format)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not parse the inventory file",
+			fmt.Sprintf("Could not parse %s as a %s inventory: %s", inventoryPath, format, err))
+		return
+	}
+
+	config.Format = types.StringValue(format)
+
+	var diagsFromInventory diag.Diagnostics
+
+	config.Hosts, config.Groups, diagsFromInventory = inventoryFileAttributes(ctx, inventory)
+	resp.Diagnostics.Append(diagsFromInventory...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// inventoryFileAttributes renders the hosts and groups of a parsed inventory as the 'hosts' and 'groups' attributes.
+func inventoryFileAttributes(
+	ctx context.Context,
+	inventory *providerutils.Inventory,
+) (types.List, types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	hostsType := types.ObjectType{AttrTypes: inventoryFileHostAttrTypes}
+	groupsType := types.ObjectType{AttrTypes: inventoryFileGroupAttrTypes}
+
+	hosts := []inventoryFileHostModel{}
+	for _, name := range slices.Sorted(maps.Keys(inventory.Hosts)) {
+		vars, varsJson, err := inventoryFileVars(inventory.HostVars(name))
+		if err != nil {
+			diags.AddError("Could not encode the host variables", fmt.Sprintf("Host %q: %s", name, err))
+			return types.ListNull(hostsType), types.ListNull(groupsType), diags
+		}
+
+		hosts = append(hosts, inventoryFileHostModel{
+			Name:     name,
+			Groups:   inventory.HostGroups(name),
+			Vars:     vars,
+			VarsJson: varsJson,
+		})
+	}
+
+	groups := []inventoryFileGroupModel{}
+	for _, name := range slices.Sorted(maps.Keys(inventory.Groups)) {
+		group := inventory.Groups[name]
+
+		children := slices.Clone(group.Children)
+		slices.Sort(children)
+
+		vars, varsJson, err := inventoryFileVars(group.Vars)
+		if err != nil {
+			diags.AddError("Could not encode the group variables", fmt.Sprintf("Group %q: %s", name, err))
+			return types.ListNull(hostsType), types.ListNull(groupsType), diags
+		}
+
+		groups = append(groups, inventoryFileGroupModel{
+			Name:     name,
+			Hosts:    inventory.GroupHosts(name),
+			Children: children,
+			Vars:     vars,
+			VarsJson: varsJson,
+		})
+	}
+
+	hostsList, diagsFromValue := types.ListValueFrom(ctx, hostsType, hosts)
+	diags.Append(diagsFromValue...)
+
+	groupsList, diagsFromValue := types.ListValueFrom(ctx, groupsType, groups)
+	diags.Append(diagsFromValue...)
+
+	return hostsList, groupsList, diags
+}
+
+// inventoryFileVars converts variables read from an inventory file into a map of strings, where values
+// which are not strings are JSON encoded, and into a JSON object.
+func inventoryFileVars(vars map[string]any) (map[string]string, string, error) {
+	stringVars := make(map[string]string, len(vars))
+
+	for key, value := range vars {
+		if str, ok := value.(string); ok {
+			stringVars[key] = str
+
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, "", fmt.Errorf("variable %q: %w", key, err)
+		}
+
+		stringVars[key] = string(encoded)
+	}
+
+	varsJson, err := json.Marshal(vars)
+	if err != nil {
+		return nil, "", fmt.Errorf("couldn't encode variables to JSON: %w", err)
+	}
+
+	return stringVars, string(varsJson), nil
+}
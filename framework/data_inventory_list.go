@@ -0,0 +1,221 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = (*InventoryListDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*InventoryListDataSource)(nil)
+)
+
+type InventoryListDataSource struct {
+	providerConfig *providerutils.ProviderConfig
+}
+
+func NewInventoryListDataSource() datasource.DataSource {
+	return &InventoryListDataSource{}
+}
+
+// Metadata implements datasource.Resource.
+func (i *InventoryListDataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_inventory_list"
+}
+
+func (i *InventoryListDataSource) Configure(
+	ctx context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerConfig, ok := req.ProviderData.(*providerutils.ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *providerutils.ProviderConfig, got %T", req.ProviderData),
+		)
+		return
+	}
+
+	i.providerConfig = providerConfig
+}
+
+type InventoryListDataSourceModel struct {
+	AnsibleInventoryBinary types.String `tfsdk:"ansible_inventory_binary"`
+	InventoryFiles         types.List   `tfsdk:"inventory_files"`
+	Limit                  types.String `tfsdk:"limit"`
+	VaultIds               types.List   `tfsdk:"vault_ids"`
+	VaultPasswordFile      types.String `tfsdk:"vault_password_file"`
+	Hosts                  types.List   `tfsdk:"hosts"`
+	Groups                 types.List   `tfsdk:"groups"`
+}
+
+// Schema implements datasource.Resource.
+func (i *InventoryListDataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This data source runs `ansible-inventory --list` and exposes the hosts and groups of the inventory, " +
+			"with their variables. Unlike `ansible_inventory_file`, it supports every inventory source Ansible supports: " +
+			"inventory plugins (e.g. `constructed` or `amazon.aws.aws_ec2`), scripts, directories and `host_vars`/`group_vars`.",
+		Attributes: map[string]schema.Attribute{
+			"ansible_inventory_binary": schema.StringAttribute{
+				MarkdownDescription: "Path to ansible-inventory executable (binary). " +
+					"Defaults to the provider's `ansible_inventory_binary`, or `ansible-inventory`.",
+				Optional: true,
+			},
+			"inventory_files": schema.ListAttribute{
+				MarkdownDescription: "Inventory sources: files, directories, scripts or inventory plugin configurations. " +
+					"Defaults to the provider's `inventory_files`. Without them, or when set to an empty list, " +
+					"the inventory configured for Ansible is used.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"limit": schema.StringAttribute{
+				MarkdownDescription: "Further limit the hosts to an additional pattern.",
+				Optional:            true,
+			},
+			"vault_ids": schema.ListAttribute{
+				MarkdownDescription: "The identities of the vaults used to decrypt the inventory variables.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"vault_password_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a vault password file. Defaults to the provider's `vault_password_file`.",
+				Optional:            true,
+			},
+			"hosts": schema.ListAttribute{
+				MarkdownDescription: "Hosts of the inventory, sorted by name, with all their groups, like the `group_names` " +
+					"variable, and their variables from `_meta.hostvars`: values which are not strings are JSON encoded in `vars`, " +
+					"and `vars_json` holds them all as a JSON object.",
+				Computed:    true,
+				ElementType: types.ObjectType{AttrTypes: inventoryFileHostAttrTypes},
+			},
+			"groups": schema.ListAttribute{
+				MarkdownDescription: "Groups of the inventory, sorted by name, with the hosts declared in the group " +
+					"and its child groups.",
+				Computed:    true,
+				ElementType: types.ObjectType{AttrTypes: inventoryFileGroupAttrTypes},
+			},
+		},
+	}
+}
+
+// Read implements datasource.Resource.
+func (i *InventoryListDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config InventoryListDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ansibleInventoryBinary := i.providerConfig.InventoryBinary(config.AnsibleInventoryBinary.ValueString())
+
+	resp.Diagnostics.Append(lookupBinary(ansibleInventoryBinary, "ansible_inventory_binary", "ansible-inventory")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := []string{"--list"}
+
+	inventoryFiles, diags := stringsPointer(ctx, config.InventoryFiles)
+	resp.Diagnostics.Append(diags...)
+
+	var vaultIds []string
+	resp.Diagnostics.Append(config.VaultIds.ElementsAs(ctx, &vaultIds, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, inventory := range i.providerConfig.PlaybookInventoryFiles(inventoryFiles) {
+		args = append(args, "--inventory", inventory)
+	}
+
+	if limit := config.Limit.ValueString(); limit != "" {
+		args = append(args, "--limit", limit)
+	}
+
+	redactor := providerutils.NewRedactor()
+
+	for _, vaultId := range vaultIds {
+		args = append(args, "--vault-id", vaultId)
+
+		// A vault ID is 'label@source' or 'source', where the source can be a password file.
+		_, source, found := strings.Cut(vaultId, "@")
+		if !found {
+			source = vaultId
+		}
+
+		redactor.AddSecretFile(source)
+	}
+
+	vaultPasswordFile := i.providerConfig.PlaybookVaultPasswordFile(config.VaultPasswordFile.ValueStringPointer())
+	defaultVaultID := i.providerConfig.PlaybookVaultID(nil)
+
+	switch {
+	case len(vaultIds) == 0 && defaultVaultID != "" && vaultPasswordFile != "":
+		// The provider's vault_id is bound to the password file, like in the ansible_playbook resource.
+		args = append(args, "--vault-id", defaultVaultID+"@"+vaultPasswordFile)
+	case vaultPasswordFile != "":
+		args = append(args, "--vault-password-file", vaultPasswordFile)
+	}
+
+	redactor.AddSecretFile(vaultPasswordFile)
+
+	tflog.Debug(ctx, fmt.Sprintf("Running Command <%s %s>", ansibleInventoryBinary,
+		strings.Join(redactor.RedactArgs(args), " ")))
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, ansibleInventoryBinary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		resp.Diagnostics.AddError("ansible-inventory failed",
+			fmt.Sprintf("%s\n\n%s", err, redactor.Redact(stderr.String())))
+		return
+	}
+
+	if stderr.Len() > 0 {
+		tflog.Warn(ctx, "LOG [ansible-inventory]: "+redactor.Redact(stderr.String()))
+	}
+
+	inventory, err := providerutils.ParseInventory(stdout.Bytes(), providerutils.InventoryFormatJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not parse the ansible-inventory output", err.Error())
+		return
+	}
+
+	var diagsFromInventory diag.Diagnostics
+
+	config.Hosts, config.Groups, diagsFromInventory = inventoryFileAttributes(ctx, inventory)
+	resp.Diagnostics.Append(diagsFromInventory...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
@@ -0,0 +1,152 @@
+package framework_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/framework"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// terraformValue converts plain Go values, maps for objects and slices for lists, into a value of the given type.
+// Missing object attributes are null, and values of dynamic attributes are given as tftypes values.
+func terraformValue(t *testing.T, typ tftypes.Type, value any) tftypes.Value {
+	t.Helper()
+
+	if value == nil {
+		return tftypes.NewValue(typ, nil)
+	}
+
+	if value, ok := value.(tftypes.Value); ok {
+		return value
+	}
+
+	switch typ := typ.(type) {
+	case tftypes.Object:
+		attributes, ok := value.(map[string]any)
+		require.True(t, ok, "expected a map, got %T", value)
+
+		values := map[string]tftypes.Value{}
+		for name, attrType := range typ.AttributeTypes {
+			values[name] = terraformValue(t, attrType, attributes[name])
+		}
+
+		for name := range attributes {
+			require.Contains(t, typ.AttributeTypes, name)
+		}
+
+		return tftypes.NewValue(typ, values)
+	case tftypes.List:
+		elements, ok := value.([]any)
+		require.True(t, ok, "expected a slice, got %T", value)
+
+		values := make([]tftypes.Value, 0, len(elements))
+		for _, element := range elements {
+			values = append(values, terraformValue(t, typ.ElementType, element))
+		}
+
+		return tftypes.NewValue(typ, values)
+	case tftypes.Map:
+		elements, ok := value.(map[string]any)
+		require.True(t, ok, "expected a map, got %T", value)
+
+		values := map[string]tftypes.Value{}
+		for key, element := range elements {
+			values[key] = terraformValue(t, typ.ElementType, element)
+		}
+
+		return tftypes.NewValue(typ, values)
+	default:
+		return tftypes.NewValue(typ, value)
+	}
+}
+
+// inventoryConfig is an ansible_inventory data source configuration.
+func inventoryConfig(t *testing.T, groups ...any) tfsdk.Config {
+	t.Helper()
+
+	ctx := context.Background()
+
+	var schemaResp datasource.SchemaResponse
+	framework.NewInventoryDataSource().Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError(), schemaResp.Diagnostics)
+
+	return tfsdk.Config{
+		Raw:    terraformValue(t, schemaResp.Schema.Type().TerraformType(ctx), map[string]any{"group": groups}),
+		Schema: schemaResp.Schema,
+	}
+}
+
+func readInventory(t *testing.T, config tfsdk.Config) (tfsdk.State, diag.Diagnostics) {
+	t.Helper()
+
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Raw: tftypes.NewValue(config.Raw.Type(), nil), Schema: config.Schema},
+	}
+	framework.NewInventoryDataSource().Read(context.Background(), datasource.ReadRequest{Config: config}, &resp)
+
+	return resp.State, resp.Diagnostics
+}
+
+func TestInventoryDataSourceChildren(t *testing.T) {
+	t.Parallel()
+
+	// Groups nested with 'children' below the nesting levels of the blocks.
+	state, diags := readInventory(t, inventoryConfig(t,
+		map[string]any{
+			"name":     "all_regions",
+			"children": []any{"eu"},
+			"group": []any{map[string]any{
+				"name": "eu",
+				"group": []any{map[string]any{
+					"name":     "eu_west",
+					"children": []any{"eu_west_1"},
+				}},
+			}},
+		},
+		map[string]any{
+			"name": "eu_west_1",
+			"host": []any{map[string]any{"name": "web-1"}},
+		},
+	))
+	require.False(t, diags.HasError(), diags)
+
+	assert.JSONEq(t, `{
+		"all_regions": {"children": {"eu": {"children": {"eu_west": {"children": {"eu_west_1": {}}}}}}},
+		"eu_west_1": {"children": {}, "hosts": {"web-1": {}}}
+	}`, getAttribute[string](t, state, "json"))
+}
+
+func TestInventoryDataSourceCyclicGroups(t *testing.T) {
+	t.Parallel()
+
+	for name, groups := range map[string][]any{
+		"own child": {
+			map[string]any{"name": "web", "children": []any{"web"}},
+		},
+		"children": {
+			map[string]any{"name": "a", "children": []any{"b"}},
+			map[string]any{"name": "b", "children": []any{"c"}},
+			map[string]any{"name": "c", "children": []any{"a"}},
+		},
+		"nested block and children": {
+			map[string]any{
+				"name":  "eu",
+				"group": []any{map[string]any{"name": "eu_west", "children": []any{"eu"}}},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, diags := readInventory(t, inventoryConfig(t, groups...))
+			require.True(t, diags.HasError())
+			assert.Equal(t, "Cyclic group hierarchy", diags.Errors()[0].Summary())
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithValidateConfig = (*InventoryDataSource)(nil)
+
+const (
+	minPort = 1
+	maxPort = 65535
+)
+
+// validGroupName matches the group names Ansible accepts without a warning: they are used as Python identifiers.
+// Other names are rejected, like the other invalid inventories.
+var validGroupName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// hostDefinition is the first definition of a host, whose variables the other definitions must agree with.
+type hostDefinition struct {
+	group string
+	vars  map[string]any
+}
+
+// inventoryValidator walks the group tree of an ansible_inventory configuration. Unknown values are skipped,
+// they are validated once known.
+type inventoryValidator struct {
+	hosts map[string]hostDefinition
+	diags diag.Diagnostics
+}
+
+// ValidateConfig implements datasource.DataSourceWithValidateConfig.
+func (i *InventoryDataSource) ValidateConfig(
+	ctx context.Context,
+	req datasource.ValidateConfigRequest,
+	resp *datasource.ValidateConfigResponse,
+) {
+	var config InventoryDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validator := &inventoryValidator{hosts: map[string]hostDefinition{}}
+	validator.validateGroups(ctx, config.Groups, 0, path.Root("group"))
+
+	resp.Diagnostics.Append(validator.diags...)
+}
+
+func (v *inventoryValidator) validateGroups(ctx context.Context, list types.List, level int, groupsPath path.Path) {
+	if list.IsNull() || list.IsUnknown() {
+		return
+	}
+
+	var groups []SharedGroupModel
+	var nestedGroups []types.List
+
+	if level < groupNestingLevel {
+		var elements []NestedGroupModel
+		v.diags.Append(list.ElementsAs(ctx, &elements, false)...)
+
+		for _, element := range elements {
+			groups = append(groups, element.SharedGroupModel)
+			nestedGroups = append(nestedGroups, element.Groups)
+		}
+	} else {
+		var elements []FinalGroupModel
+		v.diags.Append(list.ElementsAs(ctx, &elements, false)...)
+
+		for _, element := range elements {
+			groups = append(groups, element.SharedGroupModel)
+		}
+	}
+
+	names := map[string]bool{}
+
+	for index, group := range groups {
+		groupPath := groupsPath.AtListIndex(index)
+		name := group.Name.ValueString()
+
+		if !group.Name.IsUnknown() {
+			if names[name] {
+				v.diags.AddAttributeError(
+					groupPath.AtName("name"),
+					"Duplicate group name",
+					fmt.Sprintf("The group %q is declared several times at this level. "+
+						"Declare its hosts, vars and children in a single group block.", name),
+				)
+			}
+
+			names[name] = true
+
+			v.validateGroupName(name, groupPath.AtName("name"))
+		}
+
+		v.validateChildren(ctx, group.Children, groupPath.AtName("children"))
+		v.validateHosts(ctx, name, group.Hosts, groupPath.AtName("host"))
+
+		if index < len(nestedGroups) {
+			v.validateGroups(ctx, nestedGroups[index], level+1, groupPath.AtName("group"))
+		}
+	}
+}
+
+func (v *inventoryValidator) validateGroupName(name string, namePath path.Path) {
+	if !validGroupName.MatchString(name) {
+		v.diags.AddAttributeError(
+			namePath,
+			"Invalid group name",
+			fmt.Sprintf("The group name %q can't be used in some contexts, e.g. Jinja2 expressions, "+
+				"and Ansible warns about it. Group names must only contain letters, numbers and underscores, "+
+				"and must not start with a number.", name),
+		)
+	}
+}
+
+func (v *inventoryValidator) validateChildren(ctx context.Context, children types.List, childrenPath path.Path) {
+	if children.IsNull() || children.IsUnknown() {
+		return
+	}
+
+	var names []types.String
+	v.diags.Append(children.ElementsAs(ctx, &names, false)...)
+
+	for index, name := range names {
+		if !name.IsUnknown() && !name.IsNull() {
+			v.validateGroupName(name.ValueString(), childrenPath.AtListIndex(index))
+		}
+	}
+}
+
+func (v *inventoryValidator) validateHosts(ctx context.Context, group string, list types.List, hostsPath path.Path) {
+	if list.IsNull() || list.IsUnknown() {
+		return
+	}
+
+	var hosts []HostModel
+	v.diags.Append(list.ElementsAs(ctx, &hosts, false)...)
+
+	for index, host := range hosts {
+		hostPath := hostsPath.AtListIndex(index)
+
+		if port := host.AnsiblePort; !port.IsNull() && !port.IsUnknown() &&
+			(port.ValueInt64() < minPort || port.ValueInt64() > maxPort) {
+			v.diags.AddAttributeError(
+				hostPath.AtName("ansible_port"),
+				"Invalid ansible_port",
+				fmt.Sprintf("Expected the port of host %q to be between %d and %d, got %d.",
+					host.Name.ValueString(), minPort, maxPort, port.ValueInt64()),
+			)
+		}
+
+		if host.Name.IsUnknown() {
+			continue
+		}
+
+		v.validateHostDefinition(ctx, group, &host, hostPath)
+	}
+}
+
+// validateHostDefinition checks that the variables of a host declared in several groups don't conflict:
+// Ansible merges the definitions of a host, so that only one of the values would be used.
+func (v *inventoryValidator) validateHostDefinition(ctx context.Context, group string, host *HostModel, hostPath path.Path) {
+	vars := hostParameters(host)
+
+	if !host.Vars.IsUnknown() && !host.VarsJson.IsUnknown() {
+		// Invalid vars are reported when the inventory is read.
+		customVars, diags := inventoryVars(ctx, "host", host.Name.ValueString(), host.Vars, host.VarsJson)
+		if !diags.HasError() {
+			maps.Copy(vars, customVars)
+		}
+	}
+
+	name := host.Name.ValueString()
+
+	first, ok := v.hosts[name]
+	if !ok {
+		v.hosts[name] = hostDefinition{group: group, vars: vars}
+
+		return
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(vars)) {
+		firstValue, ok := first.vars[key]
+		if !ok {
+			continue
+		}
+
+		value, err := json.Marshal(vars[key])
+		if err != nil {
+			continue
+		}
+
+		firstEncoded, err := json.Marshal(firstValue)
+		if err != nil || string(firstEncoded) == string(value) {
+			continue
+		}
+
+		v.diags.AddAttributeError(
+			hostPath,
+			"Conflicting host variables",
+			fmt.Sprintf("The host %q sets %q to %s in group %q, and to %s in group %q. "+
+				"Ansible merges the definitions of a host, so that only one value would be used.",
+				name, key, firstEncoded, first.group, value, group),
+		)
+	}
+}
@@ -0,0 +1,125 @@
+package framework_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/framework"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validateInventory(t *testing.T, groups ...any) diag.Diagnostics {
+	t.Helper()
+
+	inventory, ok := framework.NewInventoryDataSource().(datasource.DataSourceWithValidateConfig)
+	require.True(t, ok)
+
+	var resp datasource.ValidateConfigResponse
+	inventory.ValidateConfig(context.Background(), datasource.ValidateConfigRequest{
+		Config: inventoryConfig(t, groups...),
+	}, &resp)
+
+	return resp.Diagnostics
+}
+
+func TestInventoryDataSourceValidateConfig(t *testing.T) {
+	t.Parallel()
+
+	// The same host in several groups, with the same variables.
+	diags := validateInventory(t,
+		map[string]any{
+			"name": "web",
+			"host": []any{map[string]any{"name": "web-1", "ansible_port": 2222, "vars": map[string]any{"tier": "front"}}},
+			"group": []any{
+				map[string]any{"name": "eu_west", "host": []any{map[string]any{"name": "web-1", "ansible_port": 2222}}},
+			},
+		},
+		map[string]any{
+			"name":      "eu",
+			"children":  []any{"eu_west"},
+			"host":      []any{map[string]any{"name": "web-1", "vars_json": `{"tier": "front"}`}},
+			"vars_json": `{"region": "eu"}`,
+		},
+	)
+
+	assert.Empty(t, diags)
+}
+
+func TestInventoryDataSourceValidateConfigInvalid(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		groups  []any
+		path    path.Path
+		summary string
+	}{
+		"duplicate group": {
+			groups: []any{
+				map[string]any{"name": "web"},
+				map[string]any{"name": "web"},
+			},
+			path:    path.Root("group").AtListIndex(1).AtName("name"),
+			summary: "Duplicate group name",
+		},
+		"duplicate nested group": {
+			groups: []any{map[string]any{
+				"name":  "eu",
+				"group": []any{map[string]any{"name": "web"}, map[string]any{"name": "web"}},
+			}},
+			path:    path.Root("group").AtListIndex(0).AtName("group").AtListIndex(1).AtName("name"),
+			summary: "Duplicate group name",
+		},
+		"invalid group name": {
+			groups:  []any{map[string]any{"name": "web-servers"}},
+			path:    path.Root("group").AtListIndex(0).AtName("name"),
+			summary: "Invalid group name",
+		},
+		"invalid child group name": {
+			groups:  []any{map[string]any{"name": "eu", "children": []any{"web", "1st"}}},
+			path:    path.Root("group").AtListIndex(0).AtName("children").AtListIndex(1),
+			summary: "Invalid group name",
+		},
+		"invalid port": {
+			groups: []any{map[string]any{
+				"name": "web",
+				"host": []any{map[string]any{"name": "web-1", "ansible_port": 65536}},
+			}},
+			path:    path.Root("group").AtListIndex(0).AtName("host").AtListIndex(0).AtName("ansible_port"),
+			summary: "Invalid ansible_port",
+		},
+		"conflicting host variables": {
+			groups: []any{
+				map[string]any{"name": "web", "host": []any{map[string]any{"name": "web-1", "ansible_user": "admin"}}},
+				map[string]any{"name": "db", "host": []any{map[string]any{"name": "web-1", "ansible_user": "deploy"}}},
+			},
+			path:    path.Root("group").AtListIndex(1).AtName("host").AtListIndex(0),
+			summary: "Conflicting host variables",
+		},
+		"conflicting custom host variables": {
+			groups: []any{
+				map[string]any{"name": "web", "host": []any{map[string]any{"name": "web-1", "vars": map[string]any{"port": "80"}}}},
+				map[string]any{"name": "db", "host": []any{map[string]any{"name": "web-1", "vars_json": `{"port": 80}`}}},
+			},
+			path:    path.Root("group").AtListIndex(1).AtName("host").AtListIndex(0),
+			summary: "Conflicting host variables",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			diags := validateInventory(t, test.groups...)
+			require.Len(t, diags, 1, diags)
+
+			assert.Equal(t, diag.SeverityError, diags[0].Severity())
+			assert.Equal(t, test.summary, diags[0].Summary())
+
+			withPath, ok := diags[0].(diag.DiagnosticWithPath)
+			require.True(t, ok)
+			assert.Equal(t, test.path, withPath.Path())
+		})
+	}
+}
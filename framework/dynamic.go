@@ -0,0 +1,180 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	errUnknownValue     = errors.New("value is not known yet")
+	errUnsupportedValue = errors.New("unsupported value")
+	errInvalidVars      = errors.New("expected an object or a map of variables")
+)
+
+// valueToGo converts a Terraform value, such as the content of a dynamic attribute, into plain Go values:
+// objects and maps become map[string]any, lists, sets and tuples []any, and numbers json.Number,
+// so that it can be encoded to JSON without losing its types.
+func valueToGo(ctx context.Context, value attr.Value) (any, error) {
+	tfValue, err := value.ToTerraformValue(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't convert value: %w", err)
+	}
+
+	return terraformValueToGo(tfValue)
+}
+
+func terraformValueToGo(value tftypes.Value) (any, error) {
+	if !value.IsKnown() {
+		return nil, errUnknownValue
+	}
+
+	if value.IsNull() {
+		return nil, nil //nolint:nilnil // null is a valid value
+	}
+
+	var err error
+
+	switch value.Type().(type) {
+	case tftypes.Map, tftypes.Object:
+		var elements map[string]tftypes.Value
+
+		err = value.As(&elements)
+		if err != nil {
+			break
+		}
+
+		result := make(map[string]any, len(elements))
+		for key, element := range elements {
+			result[key], err = terraformValueToGo(element)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return result, nil
+	case tftypes.List, tftypes.Set, tftypes.Tuple:
+		var elements []tftypes.Value
+
+		err = value.As(&elements)
+		if err != nil {
+			break
+		}
+
+		result := make([]any, 0, len(elements))
+		for _, element := range elements {
+			converted, err := terraformValueToGo(element)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, converted)
+		}
+
+		return result, nil
+	}
+
+	switch {
+	case value.Type().Is(tftypes.String):
+		var result string
+
+		err = value.As(&result)
+		if err == nil {
+			return result, nil
+		}
+	case value.Type().Is(tftypes.Number):
+		var result big.Float
+
+		err = value.As(&result)
+		if err == nil {
+			return json.Number(result.Text('f', -1)), nil
+		}
+	case value.Type().Is(tftypes.Bool):
+		var result bool
+
+		err = value.As(&result)
+		if err == nil {
+			return result, nil
+		}
+	default:
+		return nil, fmt.Errorf("%w of type %s", errUnsupportedValue, value.Type())
+	}
+
+	return nil, fmt.Errorf("couldn't convert value: %w", err)
+}
+
+// decodeValue decodes a Terraform value, such as the content of a dynamic attribute, into target,
+// using its JSON representation. Unknown fields are rejected.
+func decodeValue(ctx context.Context, value attr.Value, target any) error {
+	converted, err := valueToGo(ctx, value)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(converted)
+	if err != nil {
+		return fmt.Errorf("couldn't encode value: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	decoder.UseNumber()
+
+	err = decoder.Decode(target)
+	if err != nil {
+		return fmt.Errorf("unexpected value: %w", err)
+	}
+
+	return nil
+}
+
+// decodeVars decodes a dynamic attribute holding variables, an object or a map, into a map.
+// A null value is an empty map.
+func decodeVars(ctx context.Context, value attr.Value) (map[string]any, error) {
+	converted, err := valueToGo(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if converted == nil {
+		return map[string]any{}, nil
+	}
+
+	vars, ok := converted.(map[string]any)
+	if !ok {
+		return nil, errInvalidVars
+	}
+
+	return vars, nil
+}
+
+// intPointer converts the pointer of an optional integer attribute, nil if it is null, into an *int.
+func intPointer[T int32 | int64](value *T) *int {
+	if value == nil {
+		return nil
+	}
+
+	converted := int(*value)
+
+	return &converted
+}
+
+// stringsPointer converts an optional list of strings into a *[]string, nil if it is null.
+func stringsPointer(ctx context.Context, value types.List) (*[]string, diag.Diagnostics) {
+	if value.IsNull() {
+		return nil, nil
+	}
+
+	converted := []string{}
+	diags := value.ElementsAs(ctx, &converted, false)
+
+	return &converted, diags
+}
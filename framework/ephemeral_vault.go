@@ -0,0 +1,250 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource                   = (*vaultEphemeralResource)(nil)
+	_ ephemeral.EphemeralResourceWithConfigure      = (*vaultEphemeralResource)(nil)
+	_ ephemeral.EphemeralResourceWithValidateConfig = (*vaultEphemeralResource)(nil)
+)
+
+// vaultEphemeralResource decrypts an Ansible Vault like the ansible_vault resource, without storing
+// the plaintext in the plan or the state.
+type vaultEphemeralResource struct {
+	providerConfig *providerutils.ProviderConfig
+}
+
+func NewVaultEphemeralResource() ephemeral.EphemeralResource {
+	return &vaultEphemeralResource{}
+}
+
+func (r *vaultEphemeralResource) Metadata(
+	ctx context.Context,
+	req ephemeral.MetadataRequest,
+	resp *ephemeral.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_vault"
+}
+
+func (r *vaultEphemeralResource) Configure(
+	ctx context.Context,
+	req ephemeral.ConfigureRequest,
+	resp *ephemeral.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerConfig, ok := req.ProviderData.(*providerutils.ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *providerutils.ProviderConfig, got %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.providerConfig = providerConfig
+}
+
+type vaultEphemeralModel struct {
+	VaultFile         types.String `tfsdk:"vault_file"`
+	VaultContent      types.String `tfsdk:"vault_content"`
+	VaultPasswordFile types.String `tfsdk:"vault_password_file"`
+	VaultPassword     types.String `tfsdk:"vault_password"`
+	VaultPasswordCmd  types.List   `tfsdk:"vault_password_command"`
+	VaultID           types.String `tfsdk:"vault_id"`
+	Format            types.String `tfsdk:"format"`
+	Yaml              types.String `tfsdk:"yaml"`
+	Data              types.Map    `tfsdk:"data"`
+	Json              types.String `tfsdk:"json"`
+}
+
+func (r *vaultEphemeralResource) Schema(
+	ctx context.Context,
+	req ephemeral.SchemaRequest,
+	resp *ephemeral.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Decrypts an Ansible Vault file or string. Unlike the `ansible_vault` resource, the decrypted " +
+			"content is never stored in the plan or the state, and can only be used in ephemeral contexts, " +
+			"e.g. write-only attributes, provider configurations or other ephemeral resources.",
+		Attributes: map[string]schema.Attribute{
+			"vault_file": schema.StringAttribute{
+				MarkdownDescription: "Path to encrypted vault file. Conflicts with `vault_content`.",
+				Optional:            true,
+			},
+			"vault_content": schema.StringAttribute{
+				MarkdownDescription: "Encrypted vault content, e.g. the output of `ansible-vault encrypt_string`, " +
+					"with or without its `!vault |` tag. Conflicts with `vault_file`.",
+				Optional: true,
+			},
+			"vault_password_file": schema.StringAttribute{
+				MarkdownDescription: "Path to vault password file, or to an executable script printing the password. " +
+					"Defaults to the provider's `vault_password_file`, unless `vault_password` or `vault_password_command` is set.",
+				Optional: true,
+			},
+			"vault_password": schema.StringAttribute{
+				MarkdownDescription: "The vault password, e.g. from a variable or another ephemeral resource.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"vault_password_command": schema.ListAttribute{
+				MarkdownDescription: "Command printing the vault password, and its arguments, e.g. `[\"pass\", \"ansible/vault\"]`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"vault_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the encrypted vault, given to vault password client scripts.",
+				Optional:            true,
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "Format of the decrypted content, decoded into `data` and `json`: " +
+					"`yaml` (default), `json`, `env` (`NAME=value` lines) or `text`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(providerutils.VaultFormats...),
+				},
+			},
+			"yaml": schema.StringAttribute{
+				MarkdownDescription: "The decrypted content.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"data": schema.MapAttribute{
+				MarkdownDescription: "Top-level keys of the decrypted content: values which are not strings are JSON encoded. " +
+					"The keys of multi-document YAML content are merged, in order. Empty for `text` content.",
+				Computed:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+			},
+			"json": schema.StringAttribute{
+				MarkdownDescription: "The decrypted content as JSON, with sorted keys. Multi-document YAML content " +
+					"is a list of the documents, and `text` content a string.",
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func (r *vaultEphemeralResource) ValidateConfig(
+	ctx context.Context,
+	req ephemeral.ValidateConfigRequest,
+	resp *ephemeral.ValidateConfigResponse,
+) {
+	var config vaultEphemeralModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.VaultFile.IsNull() == config.VaultContent.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("vault_file"),
+			"Invalid vault",
+			"Exactly one of vault_file or vault_content must be set.",
+		)
+	}
+
+	if !config.VaultPasswordFile.IsNull() && !config.VaultPassword.IsNull() ||
+		!config.VaultPasswordCmd.IsNull() && (!config.VaultPasswordFile.IsNull() || !config.VaultPassword.IsNull()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("vault_password"),
+			"Conflicting vault passwords",
+			"Only one of vault_password_file, vault_password or vault_password_command can be set.",
+		)
+	}
+}
+
+func (r *vaultEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var model vaultEphemeralModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	password := providerutils.VaultPassword{Password: model.VaultPassword.ValueString()}
+
+	resp.Diagnostics.Append(model.VaultPasswordCmd.ElementsAs(ctx, &password.Command, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !password.IsSet() {
+		password.File = r.providerConfig.PlaybookVaultPasswordFile(model.VaultPasswordFile.ValueStringPointer())
+	}
+
+	if !password.IsSet() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("vault_password_file"),
+			"Missing vault password",
+			"One of vault_password_file, vault_password or vault_password_command must be set, "+
+				"or the provider's vault_password_file.",
+		)
+		return
+	}
+
+	content := []byte(strings.TrimPrefix(strings.TrimSpace(model.VaultContent.ValueString()), "!vault |"))
+	contentPath := path.Root("vault_content")
+
+	if !model.VaultFile.IsNull() {
+		var err error
+
+		contentPath = path.Root("vault_file")
+
+		content, err = os.ReadFile(model.VaultFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(contentPath, "Could not read the vault file", err.Error())
+			return
+		}
+	}
+
+	plaintext, err := providerutils.DecryptVaultWithPassword(ctx, content, password, model.VaultID.ValueString())
+	if err != nil {
+		// The vault password is masked in the error output.
+		redactor := providerutils.NewRedactor()
+		redactor.AddSecretFile(password.File)
+		redactor.AddSecrets(password.Password)
+
+		resp.Diagnostics.AddAttributeError(contentPath, "Could not decrypt the vault", redactor.Redact(err.Error()))
+		return
+	}
+
+	format := model.Format.ValueString()
+	if format == "" {
+		format = providerutils.VaultFormatYAML
+	}
+
+	data, jsonString, err := providerutils.DecodeVaultContent(plaintext, format)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("format"), "Could not decode the vault", err.Error())
+		return
+	}
+
+	model.Yaml = types.StringValue(string(plaintext))
+	model.Json = types.StringValue(jsonString)
+
+	var diagsFromData diag.Diagnostics
+
+	model.Data, diagsFromData = types.MapValueFrom(ctx, types.StringType, data)
+	resp.Diagnostics.Append(diagsFromData...)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &model)...)
+}
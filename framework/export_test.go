@@ -0,0 +1,7 @@
+package framework
+
+// Unexported functions tested by the framework_test package.
+var (
+	DecodeInventoryHosts  = decodeInventoryHosts
+	DecodeInventoryGroups = decodeInventoryGroups
+)
@@ -0,0 +1,137 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+)
+
+const (
+	inventoryHostsDescription = "List of hosts to add to the generated inventory. " +
+		"Each host is an object with a 'name', and optionally a list of 'groups' and an object of " +
+		"host variables 'vars', or an 'ansible_host' resource."
+	inventoryGroupsDescription = "List of groups to add to the generated inventory. " +
+		"Each group is an object with a 'name', and optionally a list of 'children' groups and an object of " +
+		"group variables 'vars', or an 'ansible_group' resource."
+)
+
+var errMissingName = errors.New("missing name")
+
+// inventoryHostValue is an element of a 'hosts' attribute: an object with the attributes
+// of an ansible_host resource, whose variables can also be set as 'vars'.
+//
+//nolint:tagliatelle // matches the attribute names
+type inventoryHostValue struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Groups        []string       `json:"groups"`
+	Vars          map[string]any `json:"vars"`
+	Variables     map[string]any `json:"variables"`
+	VariablesJSON string         `json:"variables_json"`
+}
+
+// inventoryGroupValue is an element of an 'inventory_groups' attribute: an object with the attributes
+// of an ansible_group resource, whose variables can also be set as 'vars'.
+//
+//nolint:tagliatelle // matches the attribute names
+type inventoryGroupValue struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Children      []string       `json:"children"`
+	Vars          map[string]any `json:"vars"`
+	Variables     map[string]any `json:"variables"`
+	VariablesJSON string         `json:"variables_json"`
+}
+
+// mergeVars merges the 'variables', 'variables_json' and 'vars' of an inventory value, in that order of precedence.
+func mergeVars(variables map[string]any, variablesJSON string, vars map[string]any) (map[string]any, error) {
+	merged := map[string]any{}
+
+	maps.Copy(merged, variables)
+
+	if variablesJSON != "" {
+		decoder := json.NewDecoder(strings.NewReader(variablesJSON))
+		decoder.UseNumber()
+
+		var decoded map[string]any
+
+		err := decoder.Decode(&decoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidVars, err)
+		}
+
+		maps.Copy(merged, decoded)
+	}
+
+	maps.Copy(merged, vars)
+
+	return merged, nil
+}
+
+// decodeInventoryHosts decodes the value of a 'hosts' attribute.
+func decodeInventoryHosts(ctx context.Context, value attr.Value) ([]providerutils.InventoryHost, error) {
+	var values []inventoryHostValue
+
+	err := decodeValue(ctx, value, &values)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]providerutils.InventoryHost, 0, len(values))
+
+	for idx, host := range values {
+		if host.Name == "" {
+			return nil, fmt.Errorf("%w of host %d", errMissingName, idx)
+		}
+
+		vars, err := mergeVars(host.Variables, host.VariablesJSON, host.Vars)
+		if err != nil {
+			return nil, fmt.Errorf("host %s: %w", host.Name, err)
+		}
+
+		hosts = append(hosts, providerutils.InventoryHost{
+			Name:   host.Name,
+			Groups: host.Groups,
+			Vars:   vars,
+		})
+	}
+
+	return hosts, nil
+}
+
+// decodeInventoryGroups decodes the value of an 'inventory_groups' attribute.
+func decodeInventoryGroups(ctx context.Context, value attr.Value) ([]providerutils.InventoryGroup, error) {
+	var values []inventoryGroupValue
+
+	err := decodeValue(ctx, value, &values)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]providerutils.InventoryGroup, 0, len(values))
+
+	for idx, group := range values {
+		if group.Name == "" {
+			return nil, fmt.Errorf("%w of group %d", errMissingName, idx)
+		}
+
+		vars, err := mergeVars(group.Variables, group.VariablesJSON, group.Vars)
+		if err != nil {
+			return nil, fmt.Errorf("group %s: %w", group.Name, err)
+		}
+
+		groups = append(groups, providerutils.InventoryGroup{
+			Name:     group.Name,
+			Children: group.Children,
+			Vars:     vars,
+		})
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,134 @@
+package framework_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/framework"
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stringList(values ...string) types.List {
+	elements := make([]attr.Value, 0, len(values))
+	for _, value := range values {
+		elements = append(elements, types.StringValue(value))
+	}
+
+	return types.ListValueMust(types.StringType, elements)
+}
+
+// ansibleHost is the value of an ansible_host resource.
+func ansibleHost(name string, groups types.List, variables map[string]string, variablesJSON string) types.Object {
+	variableValues := map[string]attr.Value{}
+	for key, value := range variables {
+		variableValues[key] = types.StringValue(value)
+	}
+
+	return types.ObjectValueMust(map[string]attr.Type{
+		"id":             types.StringType,
+		"name":           types.StringType,
+		"groups":         types.ListType{ElemType: types.StringType},
+		"variables":      types.MapType{ElemType: types.StringType},
+		"variables_json": types.StringType,
+	}, map[string]attr.Value{
+		"id":             types.StringValue(name),
+		"name":           types.StringValue(name),
+		"groups":         groups,
+		"variables":      types.MapValueMust(types.StringType, variableValues),
+		"variables_json": types.StringValue(variablesJSON),
+	})
+}
+
+func tuple(values ...attr.Value) types.Dynamic {
+	elementTypes := make([]attr.Type, 0, len(values))
+	for _, value := range values {
+		elementTypes = append(elementTypes, value.Type(context.Background()))
+	}
+
+	return types.DynamicValue(types.TupleValueMust(elementTypes, values))
+}
+
+// The 'variables_json' of an ansible_host or ansible_group take precedence over its 'variables',
+// and 'vars' over both.
+func TestDecodeInventoryHosts(t *testing.T) {
+	t.Parallel()
+
+	hosts, err := framework.DecodeInventoryHosts(context.Background(), tuple(
+		ansibleHost("web-1", stringList("web"), map[string]string{"ansible_user": "admin", "tier": "front"},
+			`{"ansible_user": "deploy", "ports": [80, 443]}`),
+		types.ObjectValueMust(map[string]attr.Type{
+			"name": types.StringType,
+			"vars": types.ObjectType{AttrTypes: map[string]attr.Type{"ansible_port": types.NumberType}},
+		}, map[string]attr.Value{
+			"name": types.StringValue("web-2"),
+			"vars": types.ObjectValueMust(
+				map[string]attr.Type{"ansible_port": types.NumberType},
+				map[string]attr.Value{"ansible_port": types.NumberValue(big.NewFloat(2222))},
+			),
+		}),
+	))
+	require.NoError(t, err)
+
+	assert.Equal(t, []providerutils.InventoryHost{
+		{
+			Name:   "web-1",
+			Groups: []string{"web"},
+			Vars: map[string]any{
+				"ansible_user": "deploy",
+				"tier":         "front",
+				"ports":        []any{json.Number("80"), json.Number("443")},
+			},
+		},
+		{Name: "web-2", Vars: map[string]any{"ansible_port": json.Number("2222")}},
+	}, hosts)
+}
+
+func TestDecodeInventoryGroups(t *testing.T) {
+	t.Parallel()
+
+	groups, err := framework.DecodeInventoryGroups(context.Background(), tuple(
+		types.ObjectValueMust(map[string]attr.Type{
+			"name":           types.StringType,
+			"children":       types.ListType{ElemType: types.StringType},
+			"variables":      types.MapType{ElemType: types.StringType},
+			"variables_json": types.StringType,
+			"vars":           types.ObjectType{AttrTypes: map[string]attr.Type{"env": types.StringType}},
+		}, map[string]attr.Value{
+			"name":           types.StringValue("production"),
+			"children":       stringList("web", "db"),
+			"variables":      types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("dev")}),
+			"variables_json": types.StringValue(`{"env": "staging", "replicas": 3}`),
+			"vars": types.ObjectValueMust(
+				map[string]attr.Type{"env": types.StringType},
+				map[string]attr.Value{"env": types.StringValue("production")},
+			),
+		}),
+	))
+	require.NoError(t, err)
+
+	assert.Equal(t, []providerutils.InventoryGroup{{
+		Name:     "production",
+		Children: []string{"web", "db"},
+		Vars:     map[string]any{"env": "production", "replicas": json.Number("3")},
+	}}, groups)
+}
+
+func TestDecodeInventoryHostsInvalid(t *testing.T) {
+	t.Parallel()
+
+	for name, value := range map[string]types.Dynamic{
+		"missing name":         tuple(ansibleHost("", types.ListNull(types.StringType), nil, "")),
+		"invalid JSON":         tuple(ansibleHost("web-1", types.ListNull(types.StringType), nil, `{"port": `)),
+		"not a list of hosts":  types.DynamicValue(types.StringValue("web-1")),
+		"JSON which is a list": tuple(ansibleHost("web-1", types.ListNull(types.StringType), nil, `[1, 2]`)),
+	} {
+		_, err := framework.DecodeInventoryHosts(context.Background(), value)
+		assert.Error(t, err, name)
+	}
+}
@@ -5,12 +5,17 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-var _ provider.Provider = &fwprovider{}
+var (
+	_ provider.Provider                       = &fwprovider{}
+	_ provider.ProviderWithEphemeralResources = &fwprovider{}
+)
 
 // New returns a new, initialized Terraform Plugin Framework-style provider instance.
 // The provider instance is fully configured once the `Configure` method has been called.
@@ -33,9 +38,60 @@ func (f *fwprovider) Metadata(
 }
 
 func (f *fwprovider) Schema(ctx context.Context, request provider.SchemaRequest, response *provider.SchemaResponse) {
+	// NOTE: this schema must stay identical to the one declared in provider/provider.go,
+	// since both servers are muxed together.
 	response.Schema = schema.Schema{
-		Attributes: map[string]schema.Attribute{},
-		Blocks:     map[string]schema.Block{},
+		Attributes: map[string]schema.Attribute{
+			"ansible_playbook_binary": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default path to ansible-playbook executable (binary).",
+			},
+			"ansible_vault_binary": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default path to ansible-vault executable (binary).",
+			},
+			"ansible_inventory_binary": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default path to ansible-inventory executable (binary).",
+			},
+			"inventory_files": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Default list of inventory files used by every playbook run.",
+			},
+			"extra_vars": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Default map of additional variables passed to every playbook run. " +
+					"Variables set on a resource or action take precedence.",
+			},
+			"extra_vars_json": schema.StringAttribute{
+				Optional: true,
+				Description: "Default variables as a JSON object, e.g. from jsonencode(), for values which are not " +
+					"strings (lists, objects, numbers, booleans). Merged on top of 'extra_vars'.",
+			},
+			"vault_password_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default path to a vault password file.",
+			},
+			"vault_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default ID of the desired vault(s).",
+			},
+			"user": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default user to connect as.",
+			},
+			"private_key_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default path to the private key file used for connections.",
+			},
+			"verbosity": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Default verbosity level between 0 and 6.",
+			},
+		},
+		Blocks: map[string]schema.Block{},
 	}
 }
 
@@ -55,11 +111,22 @@ func (f *fwprovider) Configure(
 func (f *fwprovider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewInventoryDataSource,
+		NewInventoryFileDataSource,
+		NewInventoryListDataSource,
 	}
 }
 
 func (f *fwprovider) Resources(ctx context.Context) []func() resource.Resource {
-	return nil
+	return []func() resource.Resource{
+		NewPlaybookResource,
+		NewVaultEncryptedResource,
+	}
+}
+
+func (f *fwprovider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewVaultEphemeralResource,
+	}
 }
 
 func (f *fwprovider) Actions(ctx context.Context) []func() action.Action {
@@ -0,0 +1,1759 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	sdkdiag "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+var (
+	_ resource.ResourceWithConfigure      = (*playbookResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*playbookResource)(nil)
+	_ resource.ResourceWithUpgradeState   = (*playbookResource)(nil)
+	_ resource.ResourceWithImportState    = (*playbookResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*playbookResource)(nil)
+)
+
+// defaultPlaybookTimeout applies when the `timeouts` block doesn't set `create` or `update`.
+const defaultPlaybookTimeout = 60 * time.Minute
+
+func NewPlaybookResource() resource.Resource {
+	return &playbookResource{}
+}
+
+type playbookResource struct {
+	providerConfig *providerutils.ProviderConfig
+}
+
+func (r *playbookResource) Configure(
+	ctx context.Context,
+	req resource.ConfigureRequest,
+	resp *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerConfig, ok := req.ProviderData.(*providerutils.ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *providerutils.ProviderConfig, got %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.providerConfig = providerConfig
+}
+
+func (r *playbookResource) Metadata(
+	ctx context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_playbook"
+}
+
+var playRecapAttrTypes = map[string]attr.Type{
+	"host":        types.StringType,
+	"ok":          types.Int64Type,
+	"changed":     types.Int64Type,
+	"unreachable": types.Int64Type,
+	"failed":      types.Int64Type,
+	"skipped":     types.Int64Type,
+	"rescued":     types.Int64Type,
+	"ignored":     types.Int64Type,
+}
+
+var timeoutsAttrTypes = map[string]attr.Type{
+	"create": types.StringType,
+	"update": types.StringType,
+}
+
+var taskResultAttrTypes = map[string]attr.Type{
+	"play":    types.StringType,
+	"task":    types.StringType,
+	"host":    types.StringType,
+	"status":  types.StringType,
+	"message": types.StringType,
+}
+
+//nolint:maintidx
+func (r *playbookResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// Version 0 is the SDKv2 implementation, see resource_playbook_upgrade.go.
+		Version: 1,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The host name and the playbook path, separated by a comma.",
+			},
+
+			// Required settings
+			"playbook": schema.StringAttribute{
+				Required:    true,
+				Optional:    false,
+				Description: "Path to ansible playbook.",
+			},
+
+			// Optional settings
+			"ansible_playbook_binary": schema.StringAttribute{
+				Required: false,
+				Optional: true,
+				Description: "Path to ansible-playbook executable (binary). " +
+					"Defaults to the provider's 'ansible_playbook_binary', or 'ansible-playbook'.",
+			},
+
+			"name": schema.StringAttribute{
+				Required: false,
+				Optional: true,
+				Description: "Name of the desired host on which the playbook will be executed. " +
+					"At least one of 'name', 'hosts' or 'inventory' must be set.",
+			},
+
+			"groups": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Description: "List of desired groups of the host set in 'name'.",
+			},
+
+			"hosts": schema.DynamicAttribute{
+				Required: false,
+				Optional: true,
+				Description: "List of hosts on which the playbook will be executed, in addition to 'name'. " +
+					"Each host is an object with a 'name', and optionally a list of 'groups' and an object of " +
+					"host variables 'vars', or an 'ansible_host' resource.",
+			},
+
+			"inventory_groups": schema.DynamicAttribute{
+				Required:    false,
+				Optional:    true,
+				Description: inventoryGroupsDescription,
+			},
+
+			"inventory": schema.StringAttribute{
+				Required:  false,
+				Optional:  true,
+				Sensitive: true,
+				Description: "Inventory in the JSON format, e.g. the 'json' attribute of the 'ansible_inventory' " +
+					"data source, used in addition to 'name' and 'hosts'.",
+			},
+
+			"inventory_files": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Description: "List of inventory files, used in addition to 'name', 'hosts' and 'inventory'. " +
+					"Defaults to the provider's 'inventory_files' when it isn't set, an empty list overrides it.",
+			},
+
+			"replayable": schema.BoolAttribute{
+				Required: false,
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+				Description: "" +
+					"If 'true', the playbook will be executed on every 'terraform apply' and with that, the resource" +
+					" will be recreated. " +
+					"If 'false', the playbook will be executed only on the first 'terraform apply'. " +
+					"Note, that if set to 'true', when doing 'terraform destroy', it might not show in the destroy " +
+					"output, even though the resource still gets destroyed. " +
+					"To re-run the playbook only when something changes, set it to 'false' and use 'triggers' " +
+					"or 'replay_on_content_change' instead.",
+			},
+
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				Description: "A map of arbitrary values which, when changed, re-run the playbook " +
+					"(the resource is replaced), e.g. an instance ID or a configuration version. " +
+					"Use it with 'replayable' set to 'false'.",
+			},
+
+			"ignore_playbook_failure": schema.BoolAttribute{
+				Required: false,
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "This parameter is good for testing. " +
+					"Set to 'true' if the desired playbook is meant to fail, " +
+					"but still want the resource to run successfully.",
+			},
+
+			// ansible execution commands
+			"verbosity": schema.Int64Attribute{ // verbosity is between = (0, 6)
+				Required: false,
+				Optional: true,
+				Description: "A verbosity level between 0 and 6. " +
+					"Set ansible 'verbose' parameter, which causes Ansible to print more debug messages. " +
+					"The higher the 'verbosity', the more debug details will be printed. " +
+					"Defaults to the provider's 'verbosity' when it isn't set, 0 overrides it.",
+			},
+
+			"tags": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Description: "List of tags of plays and tasks to run.",
+			},
+
+			"limit": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Description: "List of hosts to include in playbook execution.",
+			},
+
+			"check_mode": schema.BoolAttribute{
+				Required: false,
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "If 'true', playbook execution won't make any changes but " +
+					"only change predictions will be made.",
+			},
+
+			"diff_mode": schema.BoolAttribute{
+				Required: false,
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "" +
+					"If 'true', when changing (small) files and templates, differences in those files will be shown. " +
+					"Recommended usage with 'check_mode'.",
+			},
+
+			// connection configs are handled with extra_vars
+			"force_handlers": schema.BoolAttribute{
+				Required:    false,
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "If 'true', run handlers even if a task fails.",
+			},
+
+			"user": schema.StringAttribute{
+				Required: false,
+				Optional: true,
+				Description: "Connect as this user. " +
+					"Defaults to the provider's 'user' when it isn't set, an empty string overrides it.",
+			},
+
+			"private_key_file": schema.StringAttribute{
+				Required: false,
+				Optional: true,
+				Description: "Path to the private key file used for connections. " +
+					"Defaults to the provider's 'private_key_file' when it isn't set, an empty string overrides it.",
+			},
+
+			// become configs are handled with extra_vars --> these are also connection configs
+			"extra_vars": schema.DynamicAttribute{
+				Required: false,
+				Optional: true,
+				Description: "An object of additional variables as: { key-1 = value-1, key-2 = value-2, ... }. " +
+					"Values can be strings, numbers, booleans, lists or objects, and are passed to ansible-playbook " +
+					"with their types, in a temporary JSON file. Merged on top of the provider's 'extra_vars'.",
+			},
+
+			"sensitive_extra_vars": schema.MapAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Description: "A map of additional variables holding secrets, taking precedence over 'extra_vars'. " +
+					"They are neither stored in the state nor in 'args', so changing them doesn't re-run the playbook " +
+					"(use 'triggers' for that) and they are not available to the 'destroy_playbook'. " +
+					"Their values are masked in 'ansible_playbook_stdout', the task results and the logs.",
+			},
+
+			"var_files": schema.ListAttribute{ // adds @ at the beginning of filename
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Description: "List of variable files.",
+			},
+
+			// Ansible Vault
+			"vault_files": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Description: "List of vault files.",
+			},
+
+			"vault_password_file": schema.StringAttribute{
+				Required:    false,
+				Optional:    true,
+				Description: "Path to a vault password file. Defaults to the provider's 'vault_password_file' when it isn't set.",
+			},
+
+			"vault_password": schema.StringAttribute{
+				Required:  false,
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Description: "The vault password, instead of a vault password file. It isn't stored in the state, " +
+					"so it can't be used by the destroy playbook.",
+			},
+
+			"vault_password_command": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Description: "Command printing the vault password, and its arguments, instead of a vault password file.",
+			},
+
+			"vault_id": schema.StringAttribute{
+				Required: false,
+				Optional: true,
+				Description: "ID of the desired vault(s). Defaults to the provider's 'vault_id' when it isn't set, " +
+					"an empty string overrides it.",
+			},
+
+			"replay_on_content_change": schema.BoolAttribute{
+				Required: false,
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "If 'true', the playbook is re-run (the resource is replaced) only when the content it " +
+					"depends on changes: the playbook, the playbooks, task files, roles and variable files it " +
+					"references, 'var_files', 'vault_files' and the rendered arguments. " +
+					"The digests are stored in 'content_hashes'. Use it with 'replayable' set to 'false'.",
+			},
+
+			"capture_outputs": schema.BoolAttribute{
+				Required: false,
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "If 'true', the data published by the playbook with the 'set_stats' module " +
+					"is exposed in 'outputs' (and in 'host_outputs' when 'per_host' is set).",
+			},
+
+			// Destroy-time playbook
+			"destroy_playbook": schema.StringAttribute{
+				Required: false,
+				Optional: true,
+				Description: "Path to an ansible playbook executed on 'terraform destroy', before the resource " +
+					"is removed, e.g. to deregister the host from monitoring. " +
+					"It uses the same inventory and settings as 'playbook'. " +
+					"It requires 'replayable' to be 'false': replayable resources are removed from the state " +
+					"on refresh, so they are never destroyed.",
+			},
+
+			"destroy_extra_vars": schema.MapAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Description: "Additional variables for the 'destroy_playbook', merged on top of 'extra_vars'. " +
+					"Requires 'replayable' to be 'false'.",
+			},
+
+			"destroy_tags": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    false,
+				Optional:    true,
+				Description: "List of tags of plays and tasks to run in the 'destroy_playbook'. " +
+					"Requires 'replayable' to be 'false'.",
+			},
+
+			"ignore_destroy_playbook_failure": schema.BoolAttribute{
+				Required: false,
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "If 'true', the resource is destroyed even if the 'destroy_playbook' fails. " +
+					"Otherwise, the resource is kept in the state so that the destroy can be retried.",
+			},
+
+			// computed
+			// debug output
+			"args": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Used to build arguments to run Ansible playbook with. " +
+					"The extra vars are passed in a temporary file, and are not part of them.",
+			},
+
+			"content_hashes": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "SHA-256 digests of the content the playbook run depends on, by path " +
+					"(and 'args' for the rendered arguments).",
+			},
+
+			"temp_inventory_file": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Path to created temporary inventory file.",
+			},
+
+			"ansible_playbook_stdout": schema.StringAttribute{
+				Computed:    true,
+				Description: "An ansible-playbook CLI stdout output.",
+			},
+
+			"ansible_playbook_stderr": schema.StringAttribute{
+				Computed:    true,
+				Description: "An ansible-playbook CLI stderr output.",
+			},
+
+			"play_recap": schema.ListAttribute{
+				ElementType: types.ObjectType{AttrTypes: playRecapAttrTypes},
+				Computed:    true,
+				Description: "The PLAY RECAP of the last run, one element per host, with the 'host' name and " +
+					"the number of 'ok', 'changed', 'unreachable', 'failed', 'skipped', 'rescued' and 'ignored' tasks.",
+			},
+
+			"outputs": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Data published with 'set_stats' during the last run, when 'capture_outputs' is 'true'. " +
+					"String values are kept as they are, other values are JSON encoded.",
+			},
+
+			"host_outputs": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Data published with 'set_stats' and 'per_host: true' during the last run, " +
+					"when 'capture_outputs' is 'true'. Values are JSON objects keyed by host name.",
+			},
+
+			"task_results": schema.ListAttribute{
+				ElementType: types.ObjectType{AttrTypes: taskResultAttrTypes},
+				Computed:    true,
+				Description: "The result of every task of the last run, one element per task and host, " +
+					"with the 'play', 'task' and 'host' names, the 'status' (one of 'ok', 'changed', 'failed', " +
+					"'ignored', 'skipped' or 'unreachable') and the 'message' returned by the task.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			// Same block as the SDKv2 resource timeouts, so existing configurations keep working.
+			"timeouts": schema.SingleNestedBlock{
+				Attributes: map[string]schema.Attribute{
+					"create": schema.StringAttribute{
+						Optional:    true,
+						Description: "Timeout of the first playbook run, e.g. '30m'. Defaults to '60m'.",
+					},
+					"update": schema.StringAttribute{
+						Optional:    true,
+						Description: "Timeout of the playbook runs after a change, e.g. '30m'. Defaults to '60m'.",
+					},
+				},
+			},
+		},
+	}
+}
+
+type playbookResourceModel struct {
+	ID                           types.String  `tfsdk:"id"`
+	Playbook                     types.String  `tfsdk:"playbook"`
+	AnsiblePlaybookBinary        types.String  `tfsdk:"ansible_playbook_binary"`
+	Name                         types.String  `tfsdk:"name"`
+	Groups                       types.List    `tfsdk:"groups"`
+	Hosts                        types.Dynamic `tfsdk:"hosts"`
+	InventoryGroups              types.Dynamic `tfsdk:"inventory_groups"`
+	Inventory                    types.String  `tfsdk:"inventory"`
+	InventoryFiles               types.List    `tfsdk:"inventory_files"`
+	Replayable                   types.Bool    `tfsdk:"replayable"`
+	Triggers                     types.Map     `tfsdk:"triggers"`
+	IgnorePlaybookFailure        types.Bool    `tfsdk:"ignore_playbook_failure"`
+	Verbosity                    types.Int64   `tfsdk:"verbosity"`
+	Tags                         types.List    `tfsdk:"tags"`
+	Limit                        types.List    `tfsdk:"limit"`
+	CheckMode                    types.Bool    `tfsdk:"check_mode"`
+	DiffMode                     types.Bool    `tfsdk:"diff_mode"`
+	ForceHandlers                types.Bool    `tfsdk:"force_handlers"`
+	User                         types.String  `tfsdk:"user"`
+	PrivateKeyFile               types.String  `tfsdk:"private_key_file"`
+	ExtraVars                    types.Dynamic `tfsdk:"extra_vars"`
+	SensitiveExtraVars           types.Map     `tfsdk:"sensitive_extra_vars"`
+	VarFiles                     types.List    `tfsdk:"var_files"`
+	VaultFiles                   types.List    `tfsdk:"vault_files"`
+	VaultPasswordFile            types.String  `tfsdk:"vault_password_file"`
+	VaultPassword                types.String  `tfsdk:"vault_password"`
+	VaultPasswordCommand         types.List    `tfsdk:"vault_password_command"`
+	VaultID                      types.String  `tfsdk:"vault_id"`
+	ReplayOnContentChange        types.Bool    `tfsdk:"replay_on_content_change"`
+	CaptureOutputs               types.Bool    `tfsdk:"capture_outputs"`
+	DestroyPlaybook              types.String  `tfsdk:"destroy_playbook"`
+	DestroyExtraVars             types.Map     `tfsdk:"destroy_extra_vars"`
+	DestroyTags                  types.List    `tfsdk:"destroy_tags"`
+	IgnoreDestroyPlaybookFailure types.Bool    `tfsdk:"ignore_destroy_playbook_failure"`
+	Args                         types.List    `tfsdk:"args"`
+	ContentHashes                types.Map     `tfsdk:"content_hashes"`
+	TempInventoryFile            types.String  `tfsdk:"temp_inventory_file"`
+	AnsiblePlaybookStdout        types.String  `tfsdk:"ansible_playbook_stdout"`
+	AnsiblePlaybookStderr        types.String  `tfsdk:"ansible_playbook_stderr"`
+	PlayRecap                    types.List    `tfsdk:"play_recap"`
+	Outputs                      types.Map     `tfsdk:"outputs"`
+	HostOutputs                  types.Map     `tfsdk:"host_outputs"`
+	TaskResults                  types.List    `tfsdk:"task_results"`
+	Timeouts                     types.Object  `tfsdk:"timeouts"`
+}
+
+type playRecapModel struct {
+	Host        string `tfsdk:"host"`
+	Ok          int64  `tfsdk:"ok"`
+	Changed     int64  `tfsdk:"changed"`
+	Unreachable int64  `tfsdk:"unreachable"`
+	Failed      int64  `tfsdk:"failed"`
+	Skipped     int64  `tfsdk:"skipped"`
+	Rescued     int64  `tfsdk:"rescued"`
+	Ignored     int64  `tfsdk:"ignored"`
+}
+
+type taskResultModel struct {
+	Play    string `tfsdk:"play"`
+	Task    string `tfsdk:"task"`
+	Host    string `tfsdk:"host"`
+	Status  string `tfsdk:"status"`
+	Message string `tfsdk:"message"`
+}
+
+// inventoryHosts returns the hosts of the generated inventory: the host set in 'name' and the 'hosts'.
+func (m *playbookResourceModel) inventoryHosts(ctx context.Context) ([]providerutils.InventoryHost, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var hosts []providerutils.InventoryHost
+
+	if m.Name.ValueString() != "" {
+		var groups []string
+
+		diags.Append(m.Groups.ElementsAs(ctx, &groups, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		hosts = append(hosts, providerutils.InventoryHost{Name: m.Name.ValueString(), Groups: groups})
+	}
+
+	playbookHosts, err := decodeInventoryHosts(ctx, m.Hosts)
+	if err != nil {
+		diags.AddAttributeError(path.Root("hosts"), "Invalid hosts", err.Error())
+
+		return nil, diags
+	}
+
+	return append(hosts, playbookHosts...), diags
+}
+
+func (r *playbookResource) ValidateConfig(
+	ctx context.Context,
+	req resource.ValidateConfigRequest,
+	resp *resource.ValidateConfigResponse,
+) {
+	var config playbookResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Name.IsNull() && config.Hosts.IsNull() && config.Inventory.IsNull() {
+		resp.Diagnostics.AddError(
+			"No hosts specified",
+			"At least one of 'name', 'hosts' or 'inventory' must be specified",
+		)
+	}
+
+	_, err := decodeVars(ctx, config.ExtraVars)
+	if err != nil && !errors.Is(err, errUnknownValue) {
+		resp.Diagnostics.AddAttributeError(path.Root("extra_vars"), "Invalid extra_vars", err.Error())
+	}
+
+	_, err = decodeInventoryHosts(ctx, config.Hosts)
+	if err != nil && !errors.Is(err, errUnknownValue) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hosts"),
+			"Invalid hosts",
+			"Expected a list of objects with a 'name', and optionally 'groups' and 'vars': "+err.Error(),
+		)
+	}
+
+	_, err = decodeInventoryGroups(ctx, config.InventoryGroups)
+	if err != nil && !errors.Is(err, errUnknownValue) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("inventory_groups"),
+			"Invalid inventory groups",
+			"Expected a list of objects with a 'name', and optionally 'children' and 'vars': "+err.Error(),
+		)
+	}
+
+	// Replayable resources are removed from the state on refresh, to be created again,
+	// so they are never destroyed and the destroy playbook would never run.
+	if config.Replayable.IsNull() || config.Replayable.ValueBool() {
+		destroySettings := map[string]attr.Value{
+			"destroy_playbook":   config.DestroyPlaybook,
+			"destroy_extra_vars": config.DestroyExtraVars,
+			"destroy_tags":       config.DestroyTags,
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(destroySettings)) {
+			if !destroySettings[name].IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(name),
+					"Invalid "+name,
+					fmt.Sprintf("'%s' requires 'replayable = false': replayable resources are removed from the state "+
+						"on refresh, to be created again, so the destroy playbook would never run.", name),
+				)
+			}
+		}
+	}
+
+	// The inventory is sensitive, so it isn't shown.
+	if !config.Inventory.IsUnknown() && !config.Inventory.IsNull() && !isJSON(config.Inventory.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("inventory"),
+			"Invalid JSON",
+			"Expected the inventory to contain valid JSON",
+		)
+	}
+}
+
+// argsKnown reports whether all the settings used to render the arguments are known,
+// values known only after apply are rendered during apply.
+func (m *playbookResourceModel) argsKnown(ctx context.Context) bool {
+	values := []attr.Value{
+		m.Playbook, m.Name, m.Verbosity, m.Tags, m.Limit, m.CheckMode, m.DiffMode, m.ForceHandlers,
+		m.User, m.PrivateKeyFile, m.ExtraVars, m.VarFiles, m.VaultFiles, m.VaultPasswordFile, m.VaultPassword, m.VaultPasswordCommand, m.VaultID,
+	}
+
+	for _, value := range values {
+		tfValue, err := value.ToTerraformValue(ctx)
+		if err != nil || !tfValue.IsFullyKnown() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// buildArgs renders the ansible-playbook arguments (except the inventory) from the resource settings.
+func (m *playbookResourceModel) buildArgs(
+	ctx context.Context,
+	providerConfig *providerutils.ProviderConfig,
+) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var tags, limit, varFiles, vaultFiles []string
+
+	diags.Append(m.Tags.ElementsAs(ctx, &tags, false)...)
+	diags.Append(m.Limit.ElementsAs(ctx, &limit, false)...)
+	diags.Append(m.VarFiles.ElementsAs(ctx, &varFiles, false)...)
+	diags.Append(m.VaultFiles.ElementsAs(ctx, &vaultFiles, false)...)
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	vaultPassword, diagsFromPassword := m.vaultPassword(ctx, providerConfig)
+	diags.Append(diagsFromPassword...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	vaultID := providerConfig.PlaybookVaultID(m.VaultID.ValueStringPointer())
+
+	/********************
+	* 	PREP THE OPTIONS (ARGS)
+	 */
+	args := []string{}
+
+	verbose := providerutils.CreateVerboseSwitch(providerConfig.PlaybookVerbosity(intPointer(m.Verbosity.ValueInt64Pointer())))
+	if verbose != "" {
+		args = append(args, verbose)
+	}
+
+	if m.ForceHandlers.ValueBool() {
+		args = append(args, "--force-handlers")
+	}
+
+	user := providerConfig.PlaybookUser(m.User.ValueStringPointer())
+	if user != "" {
+		args = append(args, "--user", user)
+	}
+
+	privateKeyFile := providerConfig.PlaybookPrivateKeyFile(m.PrivateKeyFile.ValueStringPointer())
+	if privateKeyFile != "" {
+		args = append(args, "--private-key", privateKeyFile)
+	}
+
+	if m.Name.ValueString() != "" {
+		args = append(args, "-e", "hostname="+m.Name.ValueString())
+	}
+
+	if len(tags) > 0 {
+		args = append(args, "--tags", strings.Join(tags, ","))
+	}
+
+	if len(limit) > 0 {
+		args = append(args, "--limit", strings.Join(limit, ","))
+	}
+
+	if m.CheckMode.ValueBool() {
+		args = append(args, "--check")
+	}
+
+	if m.DiffMode.ValueBool() {
+		args = append(args, "--diff")
+	}
+
+	for _, varFile := range varFiles {
+		args = append(args, "-e", "@"+varFile)
+	}
+
+	// Ansible vault
+	if len(vaultFiles) != 0 {
+		for _, vaultFile := range vaultFiles {
+			args = append(args, "-e", "@"+vaultFile)
+		}
+
+		if !vaultPassword.IsSet() {
+			diags.AddAttributeError(
+				path.Root("vault_password_file"),
+				"vault_password_file is not found",
+				"Can not access vault_files without passing the vault_password_file, vault_password or vault_password_command",
+			)
+
+			return nil, diags
+		}
+
+		// A vault password which isn't read from a file is written to a temporary file when running ansible-playbook.
+		args = append(args, "--vault-id", vaultID+"@"+vaultPassword.Placeholder())
+	}
+
+	args = append(args, m.Playbook.ValueString())
+
+	return args, diags
+}
+
+// vaultPassword is the source of the vault password: 'vault_password', which is write-only and must be read
+// from the configuration, 'vault_password_command', or the vault password file, defaulting to the provider's.
+func (m *playbookResourceModel) vaultPassword(
+	ctx context.Context,
+	providerConfig *providerutils.ProviderConfig,
+) (providerutils.VaultPassword, diag.Diagnostics) {
+	vaultPassword := providerutils.VaultPassword{Password: m.VaultPassword.ValueString()}
+
+	diags := m.VaultPasswordCommand.ElementsAs(ctx, &vaultPassword.Command, false)
+
+	if !vaultPassword.IsSet() {
+		vaultPassword.File = providerConfig.PlaybookVaultPasswordFile(m.VaultPasswordFile.ValueStringPointer())
+	}
+
+	return vaultPassword, diags
+}
+
+// extraVars merges 'extra_vars' on top of the provider defaults. They are passed to ansible-playbook
+// in a temporary file, see execute, rather than in the rendered arguments.
+func (m *playbookResourceModel) extraVars(
+	ctx context.Context,
+	providerConfig *providerutils.ProviderConfig,
+) (map[string]any, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	extraVars, err := decodeVars(ctx, m.ExtraVars)
+	if err != nil {
+		diags.AddAttributeError(path.Root("extra_vars"), "Invalid extra_vars", err.Error())
+
+		return nil, diags
+	}
+
+	return providerConfig.PlaybookExtraVars(extraVars), diags
+}
+
+// contentHashes computes the digests of the playbook, the files it depends on, the rendered args
+// and the extra vars.
+func (m *playbookResourceModel) contentHashes(
+	ctx context.Context,
+	args []string,
+	extraVars map[string]any,
+) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var varFiles, vaultFiles []string
+
+	diags.Append(m.VarFiles.ElementsAs(ctx, &varFiles, false)...)
+	diags.Append(m.VaultFiles.ElementsAs(ctx, &vaultFiles, false)...)
+
+	if diags.HasError() {
+		return types.MapNull(types.StringType), diags
+	}
+
+	contentHashes, err := providerutils.ContentHashes(
+		m.Playbook.ValueString(),
+		slices.Concat(varFiles, vaultFiles),
+		args,
+		extraVars,
+	)
+	if err != nil {
+		diags.AddError("Failed to compute the playbook content hashes", err.Error())
+
+		return types.MapNull(types.StringType), diags
+	}
+
+	value, diagsFromMap := types.MapValueFrom(ctx, types.StringType, contentHashes)
+	diags.Append(diagsFromMap...)
+
+	return value, diags
+}
+
+// ModifyPlan renders the arguments at plan time, so that a change of the rendered arguments or extra vars
+// (e.g. of the provider defaults) re-runs the playbook, and plans a replacement when
+// 'replay_on_content_change' is set and the content hashes differ from the ones of the last run.
+func (r *playbookResource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	// Destroy plan
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan playbookResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringUnknown()
+	if !plan.Name.IsUnknown() && !plan.Playbook.IsUnknown() {
+		plan.ID = types.StringValue(playbookID(plan.Name.ValueString(), plan.Playbook.ValueString()))
+	}
+
+	resp.Diagnostics.Append(validateImported(ctx, req.State, plan)...)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The write-only vault_password is only in the configuration, and mustn't be planned.
+	argsModel := plan
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("vault_password"), &argsModel.VaultPassword)...)
+	if resp.Diagnostics.HasError() || !argsModel.argsKnown(ctx) {
+		return
+	}
+
+	args, diags := argsModel.buildArgs(ctx, r.providerConfig)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Args, diags = types.ListValueFrom(ctx, types.StringType, args)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	extraVars, diags := plan.extraVars(ctx, r.providerConfig)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// On create, the content is hashed during apply, since it might be generated by other resources.
+	if req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	var state playbookResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.ReplayOnContentChange.ValueBool() {
+		contentHashes, diags := plan.contentHashes(ctx, args, extraVars)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.ContentHashes = contentHashes
+		// An imported resource has no content hashes yet.
+		if !state.ContentHashes.IsNull() && !contentHashes.Equal(state.ContentHashes) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("content_hashes"))
+		}
+	} else if stateArgsHash, ok := state.ContentHashes.Elements()[providerutils.ArgsContentHashKey]; ok {
+		// The extra vars aren't part of 'args', their changes show up in the args hash of the last run.
+		argsHash, err := providerutils.ArgsContentHash(args, extraVars)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("extra_vars"), "Invalid extra_vars", err.Error())
+
+			return
+		}
+
+		if !types.StringValue(argsHash).Equal(stateArgsHash) {
+			plan.ContentHashes = types.MapUnknown(types.StringType)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || resp.Plan.Raw.Equal(req.State.Raw) {
+		return
+	}
+
+	keepsLastRun, diags := plan.keepsLastRun(ctx, state, req.State)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if keepsLastRun {
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	// The playbook is re-run, so the results of the last run are replaced.
+	if !plan.ReplayOnContentChange.ValueBool() {
+		plan.ContentHashes = types.MapUnknown(types.StringType)
+	}
+
+	plan.AnsiblePlaybookStdout = types.StringUnknown()
+	plan.AnsiblePlaybookStderr = types.StringUnknown()
+	plan.PlayRecap = types.ListUnknown(types.ObjectType{AttrTypes: playRecapAttrTypes})
+	plan.TaskResults = types.ListUnknown(types.ObjectType{AttrTypes: taskResultAttrTypes})
+	plan.Outputs = types.MapUnknown(types.StringType)
+	plan.HostOutputs = types.MapUnknown(types.StringType)
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// validateImported requires 'replayable = false' for an imported resource, until its settings are adopted.
+// A replayable resource would be removed from the state on the next refresh, and the playbook run again.
+func validateImported(ctx context.Context, state tfsdk.State, plan playbookResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if state.Raw.IsNull() || plan.Replayable.IsUnknown() || !plan.Replayable.ValueBool() {
+		return diags
+	}
+
+	var args types.List
+
+	diags.Append(state.GetAttribute(ctx, path.Root("args"), &args)...)
+	if diags.HasError() || !args.IsNull() {
+		return diags
+	}
+
+	diags.AddAttributeError(
+		path.Root("replayable"),
+		"Invalid replayable",
+		"An imported ansible_playbook resource requires 'replayable = false': replayable resources are removed "+
+			"from the state on refresh, so the imported host would be configured again.",
+	)
+
+	return diags
+}
+
+// keepsLastRun reports whether the plan only unsets the 'verbosity', 'vault_password_file' or 'vault_id'
+// stored as 0 or "" by the SDKv2 resource, without changing the rendered arguments. The results of the
+// last run are then kept in the plan, so that Update doesn't re-run the playbook.
+func (m *playbookResourceModel) keepsLastRun(
+	ctx context.Context,
+	state playbookResourceModel,
+	stateData tfsdk.State,
+) (bool, diag.Diagnostics) {
+	kept := *m
+
+	if kept.Verbosity.IsNull() && state.Verbosity.Equal(types.Int64Value(0)) {
+		kept.Verbosity = state.Verbosity
+	}
+
+	if kept.VaultPasswordFile.IsNull() && state.VaultPasswordFile.Equal(types.StringValue("")) {
+		kept.VaultPasswordFile = state.VaultPasswordFile
+	}
+
+	if kept.VaultID.IsNull() && state.VaultID.Equal(types.StringValue("")) {
+		kept.VaultID = state.VaultID
+	}
+
+	kept.setLastRun(state)
+
+	keptData := tfsdk.Plan{Schema: stateData.Schema}
+
+	diags := keptData.Set(ctx, &kept)
+	if diags.HasError() || !keptData.Raw.Equal(stateData.Raw) {
+		return false, diags
+	}
+
+	m.setLastRun(state)
+
+	return true, diags
+}
+
+// setLastRun copies the results of the last run from the state, and the content hashes unless they were computed.
+func (m *playbookResourceModel) setLastRun(state playbookResourceModel) {
+	if m.ContentHashes.IsUnknown() {
+		m.ContentHashes = state.ContentHashes
+	}
+
+	m.AnsiblePlaybookStdout = state.AnsiblePlaybookStdout
+	m.AnsiblePlaybookStderr = state.AnsiblePlaybookStderr
+	m.PlayRecap = state.PlayRecap
+	m.TaskResults = state.TaskResults
+	m.Outputs = state.Outputs
+	m.HostOutputs = state.HostOutputs
+}
+
+func (r *playbookResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan playbookResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var sensitiveExtraVars map[string]string
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sensitive_extra_vars"), &sensitiveExtraVars)...)
+
+	// The write-only vault_password is removed from the state by the framework.
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("vault_password"), &plan.VaultPassword)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.timeout("create")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	plan.ID = types.StringValue(playbookID(plan.Name.ValueString(), plan.Playbook.ValueString()))
+	plan.TempInventoryFile = types.StringValue("")
+
+	resp.Diagnostics.Append(r.run(ctx, &plan, sensitiveExtraVars)...)
+
+	// The state is saved even if the run failed, so that the resource is tainted.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *playbookResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state playbookResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// if (replayable == true) --> then we want to recreate (reapply) this resource: exits == false
+	// if (replayable == false) --> we don't want to recreate (reapply) this resource: exists == true
+	if state.Replayable.ValueBool() {
+		// Forget the resource, without running Delete (and its destroy playbook).
+		resp.State.RemoveResource(ctx)
+	}
+}
+
+func (r *playbookResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan playbookResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state playbookResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An imported host was configured before Terraform managed it,
+	// so its settings are adopted without running the playbook.
+	if state.Args.IsNull() {
+		resp.Diagnostics.Append(plan.adopt(ctx, r.providerConfig)...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	// ModifyPlan kept the results of the last run, see keepsLastRun.
+	if !plan.AnsiblePlaybookStdout.IsUnknown() {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	var sensitiveExtraVars map[string]string
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sensitive_extra_vars"), &sensitiveExtraVars)...)
+
+	// The write-only vault_password is removed from the state by the framework.
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("vault_password"), &plan.VaultPassword)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.timeout("update")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.run(ctx, &plan, sensitiveExtraVars)...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// timeout is the 'create' or 'update' timeout of the 'timeouts' block, defaultPlaybookTimeout if it isn't set.
+func (m *playbookResourceModel) timeout(name string) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if m.Timeouts.IsNull() {
+		return defaultPlaybookTimeout, diags
+	}
+
+	value, ok := m.Timeouts.Attributes()[name].(types.String)
+	if !ok || value.ValueString() == "" {
+		return defaultPlaybookTimeout, diags
+	}
+
+	timeout, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("timeouts").AtName(name),
+			"Invalid timeout",
+			fmt.Sprintf("Expected a duration such as '30m', got %q: %s", value.ValueString(), err),
+		)
+	}
+
+	return timeout, diags
+}
+
+// playbookID is the ID of an ansible_playbook resource, the playbook alone if 'name' isn't set.
+// Host names can't contain commas, so the ID is split on the first one on import.
+func playbookID(name string, playbook string) string {
+	if name == "" {
+		return playbook
+	}
+
+	return name + "," + playbook
+}
+
+// ImportState adopts a host which was configured before Terraform managed it.
+// The imported resource isn't replayable, and its configuration must set 'replayable = false', see validateImported.
+// The next apply records its settings without running the playbook.
+func (r *playbookResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	name, playbook, found := strings.Cut(req.ID, ",")
+	if !found {
+		name, playbook = "", req.ID
+	}
+
+	if (found && name == "") || playbook == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected import identifier",
+			fmt.Sprintf("Expected an import identifier with the format '<name>,<playbook>' or '<playbook>', got %q", req.ID),
+		)
+		return
+	}
+
+	nameValue := types.StringNull()
+	if name != "" {
+		nameValue = types.StringValue(name)
+	}
+
+	state := playbookResourceModel{
+		ID:                           types.StringValue(playbookID(name, playbook)),
+		Playbook:                     types.StringValue(playbook),
+		AnsiblePlaybookBinary:        types.StringNull(),
+		Name:                         nameValue,
+		Groups:                       types.ListNull(types.StringType),
+		Hosts:                        types.DynamicNull(),
+		InventoryGroups:              types.DynamicNull(),
+		Inventory:                    types.StringNull(),
+		InventoryFiles:               types.ListNull(types.StringType),
+		Replayable:                   types.BoolValue(false),
+		Triggers:                     types.MapNull(types.StringType),
+		IgnorePlaybookFailure:        types.BoolValue(false),
+		Verbosity:                    types.Int64Null(),
+		Tags:                         types.ListNull(types.StringType),
+		Limit:                        types.ListNull(types.StringType),
+		CheckMode:                    types.BoolValue(false),
+		DiffMode:                     types.BoolValue(false),
+		ForceHandlers:                types.BoolValue(false),
+		User:                         types.StringNull(),
+		PrivateKeyFile:               types.StringNull(),
+		ExtraVars:                    types.DynamicNull(),
+		SensitiveExtraVars:           types.MapNull(types.StringType),
+		VarFiles:                     types.ListNull(types.StringType),
+		VaultFiles:                   types.ListNull(types.StringType),
+		VaultPasswordFile:            types.StringNull(),
+		VaultPassword:                types.StringNull(),
+		VaultPasswordCommand:         types.ListNull(types.StringType),
+		VaultID:                      types.StringNull(),
+		ReplayOnContentChange:        types.BoolValue(false),
+		CaptureOutputs:               types.BoolValue(false),
+		DestroyPlaybook:              types.StringNull(),
+		DestroyExtraVars:             types.MapNull(types.StringType),
+		DestroyTags:                  types.ListNull(types.StringType),
+		IgnoreDestroyPlaybookFailure: types.BoolValue(false),
+		Args:                         types.ListNull(types.StringType),
+		ContentHashes:                types.MapNull(types.StringType),
+		TempInventoryFile:            types.StringValue(""),
+		AnsiblePlaybookStdout:        types.StringNull(),
+		AnsiblePlaybookStderr:        types.StringNull(),
+		PlayRecap:                    types.ListNull(types.ObjectType{AttrTypes: playRecapAttrTypes}),
+		Outputs:                      types.MapNull(types.StringType),
+		HostOutputs:                  types.MapNull(types.StringType),
+		TaskResults:                  types.ListNull(types.ObjectType{AttrTypes: taskResultAttrTypes}),
+		Timeouts:                     types.ObjectNull(timeoutsAttrTypes),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// adopt records the settings of an imported resource, as if the playbook had run without output.
+func (m *playbookResourceModel) adopt(
+	ctx context.Context,
+	providerConfig *providerutils.ProviderConfig,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	args, diagsFromArgs := m.buildArgs(ctx, providerConfig)
+	diags.Append(diagsFromArgs...)
+	if diags.HasError() {
+		return diags
+	}
+
+	m.Args, diagsFromArgs = types.ListValueFrom(ctx, types.StringType, args)
+	diags.Append(diagsFromArgs...)
+
+	extraVars, diagsFromVars := m.extraVars(ctx, providerConfig)
+	diags.Append(diagsFromVars...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if m.ContentHashes.IsUnknown() || m.ContentHashes.IsNull() {
+		var diagsFromHashes diag.Diagnostics
+
+		m.ContentHashes, diagsFromHashes = m.contentHashes(ctx, args, extraVars)
+		diags.Append(diagsFromHashes...)
+	}
+
+	m.AnsiblePlaybookStdout = types.StringValue("")
+	m.AnsiblePlaybookStderr = types.StringValue("")
+	m.TempInventoryFile = types.StringValue("")
+	m.PlayRecap = types.ListValueMust(types.ObjectType{AttrTypes: playRecapAttrTypes}, []attr.Value{})
+	m.TaskResults = types.ListValueMust(types.ObjectType{AttrTypes: taskResultAttrTypes}, []attr.Value{})
+	m.Outputs = types.MapValueMust(types.StringType, map[string]attr.Value{})
+	m.HostOutputs = types.MapValueMust(types.StringType, map[string]attr.Value{})
+
+	return diags
+}
+
+// On "terraform destroy", the 'destroy_playbook' (if any) is executed before the resource is removed.
+func (r *playbookResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state playbookResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || state.DestroyPlaybook.ValueString() == "" {
+		return
+	}
+
+	diags := r.runDestroyPlaybook(ctx, state)
+	if !diags.HasError() || !state.IgnoreDestroyPlaybookFailure.ValueBool() {
+		// The resource is kept in the state on errors, so that the destroy can be retried.
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	// The destroy playbook couldn't run or failed, e.g. without ansible-playbook: the resource is removed anyway.
+	for _, d := range diags {
+		resp.Diagnostics.AddWarning(d.Summary(), d.Detail())
+	}
+}
+
+// runDestroyPlaybook runs the 'destroy_playbook' with the same settings as the playbook, except for the playbook,
+// tags and extra vars.
+func (r *playbookResource) runDestroyPlaybook(ctx context.Context, state playbookResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var destroyExtraVars map[string]string
+
+	diags.Append(state.DestroyExtraVars.ElementsAs(ctx, &destroyExtraVars, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	extraVars, diagsFromVars := state.extraVars(ctx, r.providerConfig)
+	diags.Append(diagsFromVars...)
+	if diags.HasError() {
+		return diags
+	}
+
+	for key, value := range destroyExtraVars {
+		extraVars[key] = value
+	}
+
+	destroy := state
+	destroy.Playbook = state.DestroyPlaybook
+	destroy.Tags = state.DestroyTags
+
+	args, diagsFromArgs := destroy.buildArgs(ctx, r.providerConfig)
+	diags.Append(diagsFromArgs...)
+	if diags.HasError() {
+		return diags
+	}
+
+	tflog.Info(ctx, "LOG [ansible-playbook]: destroy playbook = "+destroy.Playbook.ValueString())
+
+	redactor, diagsFromRedactor := destroy.redactor(ctx, r.providerConfig, extraVars, nil)
+	diags.Append(diagsFromRedactor...)
+	if diags.HasError() {
+		return diags
+	}
+
+	execution, diagsFromExecute := r.execute(ctx, &destroy, args, extraVars, redactor)
+	diags.Append(diagsFromExecute...)
+	if execution == nil {
+		return diags
+	}
+
+	err := execution.resultsCallback.Cleanup()
+	if err != nil {
+		tflog.Warn(ctx, err.Error())
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("LOG [ansible-playbook]: %s", execution.output))
+
+	if execution.err != nil {
+		diags.AddError("ansible-playbook destroy playbook failed", string(execution.output))
+	}
+
+	return diags
+}
+
+// run renders the arguments and the content hashes (unless known at plan time),
+// runs the playbook and stores its results in the model.
+func (r *playbookResource) run(
+	ctx context.Context,
+	model *playbookResourceModel,
+	sensitiveExtraVars map[string]string,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	tflog.Info(ctx, "LOG [ansible-playbook]: playbook = "+model.Playbook.ValueString())
+
+	args, diagsFromArgs := model.buildArgs(ctx, r.providerConfig)
+	diags.Append(diagsFromArgs...)
+	if diags.HasError() {
+		return diags
+	}
+
+	model.Args, diagsFromArgs = types.ListValueFrom(ctx, types.StringType, args)
+	diags.Append(diagsFromArgs...)
+
+	extraVars, diagsFromVars := model.extraVars(ctx, r.providerConfig)
+	diags.Append(diagsFromVars...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if model.ContentHashes.IsUnknown() || model.ContentHashes.IsNull() {
+		var diagsFromHashes diag.Diagnostics
+
+		model.ContentHashes, diagsFromHashes = model.contentHashes(ctx, args, extraVars)
+		diags.Append(diagsFromHashes...)
+	}
+
+	if diags.HasError() {
+		return diags
+	}
+
+	// Secrets are only merged into the extra vars file, after hashing, and masked in the output.
+	runExtraVars := maps.Clone(extraVars)
+
+	for key, value := range sensitiveExtraVars {
+		runExtraVars[key] = value
+	}
+
+	redactor, diagsFromRedactor := model.redactor(ctx, r.providerConfig, runExtraVars, sensitiveExtraVars)
+	diags.Append(diagsFromRedactor...)
+	if diags.HasError() {
+		return diags
+	}
+
+	execution, diagsFromRun := r.execute(ctx, model, args, runExtraVars, redactor)
+	diags.Append(diagsFromRun...)
+	if execution == nil {
+		return diags
+	}
+
+	defer func() {
+		err := execution.resultsCallback.Cleanup()
+		if err != nil {
+			tflog.Warn(ctx, err.Error())
+		}
+	}()
+
+	ansiblePlayStderrString := ""
+
+	if execution.err != nil {
+		playbookFailMsg := string(execution.output)
+		if !model.IgnorePlaybookFailure.ValueBool() {
+			diags.AddError("ansible-playbook failed", playbookFailMsg)
+		} else {
+			diags.AddWarning("ansible-playbook failed", playbookFailMsg)
+		}
+
+		ansiblePlayStderrString = execution.err.Error()
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("LOG [ansible-playbook]: %s", execution.output))
+
+	model.AnsiblePlaybookStdout = types.StringValue(string(execution.output))
+	model.AnsiblePlaybookStderr = types.StringValue(ansiblePlayStderrString)
+	model.TempInventoryFile = types.StringValue("")
+
+	diags.Append(model.setResults(ctx, execution)...)
+
+	return diags
+}
+
+// setResults stores the structured results of the run, read from the results callback.
+// The task messages are redacted, the outputs are published on purpose by the playbook.
+func (m *playbookResourceModel) setResults(
+	ctx context.Context,
+	execution *playbookExecution,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	results, err := execution.resultsCallback.Results()
+	if err != nil {
+		diags.AddError("Failed to read the playbook results", err.Error())
+		return diags
+	}
+
+	playRecap := make([]playRecapModel, 0, len(results.Recap))
+	for _, recap := range results.Recap {
+		playRecap = append(playRecap, playRecapModel(recap))
+	}
+
+	taskResults := make([]taskResultModel, 0, len(results.Tasks))
+	for _, task := range results.Tasks {
+		task.Message = execution.redactor.Redact(task.Message)
+		taskResults = append(taskResults, taskResultModel(task))
+	}
+
+	outputs, hostOutputs := map[string]string{}, map[string]string{}
+
+	if m.CaptureOutputs.ValueBool() {
+		outputs, err = results.Outputs()
+		if err != nil {
+			diags.AddError("Failed to read the playbook outputs", err.Error())
+		}
+
+		hostOutputs, err = results.HostOutputs()
+		if err != nil {
+			diags.AddError("Failed to read the playbook outputs", err.Error())
+		}
+	}
+
+	var diagsFromValue diag.Diagnostics
+
+	m.PlayRecap, diagsFromValue = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: playRecapAttrTypes}, playRecap)
+	diags.Append(diagsFromValue...)
+
+	m.TaskResults, diagsFromValue = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: taskResultAttrTypes}, taskResults)
+	diags.Append(diagsFromValue...)
+
+	m.Outputs, diagsFromValue = types.MapValueFrom(ctx, types.StringType, outputs)
+	diags.Append(diagsFromValue...)
+
+	m.HostOutputs, diagsFromValue = types.MapValueFrom(ctx, types.StringType, hostOutputs)
+	diags.Append(diagsFromValue...)
+
+	return diags
+}
+
+// playbookExecution is the outcome of an ansible-playbook run.
+type playbookExecution struct {
+	// output is redacted.
+	output []byte
+	err    error
+	// resultsCallback must be cleaned up by the caller.
+	resultsCallback *providerutils.ResultsCallback
+	redactor        *providerutils.Redactor
+}
+
+// redactor masks the secrets of a run in its logs, output and diagnostics: the sensitive extra vars,
+// the passwords of the extra vars and inventories, and the vault password or the content of its file.
+func (m *playbookResourceModel) redactor(
+	ctx context.Context,
+	providerConfig *providerutils.ProviderConfig,
+	extraVars map[string]any,
+	sensitiveExtraVars map[string]string,
+) (*providerutils.Redactor, diag.Diagnostics) {
+	redactor := providerutils.NewRedactor()
+
+	for _, value := range sensitiveExtraVars {
+		redactor.AddSecrets(value)
+	}
+
+	redactor.AddPasswordVars(extraVars)
+
+	hosts, diags := m.inventoryHosts(ctx)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	for _, host := range hosts {
+		redactor.AddPasswordVars(host.Vars)
+	}
+
+	groups, err := decodeInventoryGroups(ctx, m.InventoryGroups)
+	if err != nil {
+		diags.AddAttributeError(path.Root("inventory_groups"), "Invalid inventory groups", err.Error())
+
+		return nil, diags
+	}
+
+	for _, group := range groups {
+		redactor.AddPasswordVars(group.Vars)
+	}
+
+	redactor.AddInventoryPasswords(m.Inventory.ValueString())
+
+	vaultPassword, diagsFromPassword := m.vaultPassword(ctx, providerConfig)
+	diags.Append(diagsFromPassword...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	redactor.AddSecretFile(vaultPassword.File)
+	redactor.AddSecrets(vaultPassword.Password)
+
+	return redactor, diags
+}
+
+// execute runs ansible-playbook with the given arguments, against temporary inventories
+// built from the resource's 'name', 'groups', 'hosts', 'inventory_groups' and 'inventory' (removed afterwards)
+// and the provider's inventory files. The redactor, see redactor, masks the secrets in the logs and the output.
+// A nil execution is returned if ansible-playbook couldn't be started.
+func (r *playbookResource) execute(
+	ctx context.Context,
+	model *playbookResourceModel,
+	argsTf []string,
+	extraVars map[string]any,
+	redactor *providerutils.Redactor,
+) (*playbookExecution, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	hosts, diagsFromHosts := model.inventoryHosts(ctx)
+	diags.Append(diagsFromHosts...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	groups, err := decodeInventoryGroups(ctx, model.InventoryGroups)
+	if err != nil {
+		diags.AddAttributeError(path.Root("inventory_groups"), "Invalid inventory groups", err.Error())
+
+		return nil, diags
+	}
+
+	inventoryFiles, diagsFromInventoryFiles := stringsPointer(ctx, model.InventoryFiles)
+	diags.Append(diagsFromInventoryFiles...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	vaultPassword, diagsFromPassword := model.vaultPassword(ctx, r.providerConfig)
+	diags.Append(diagsFromPassword...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	ansiblePlaybookBinary := r.providerConfig.PlaybookBinary(model.AnsiblePlaybookBinary.ValueString())
+
+	diags.Append(lookupBinary(ansiblePlaybookBinary, "ansible_playbook_binary", "ansible-playbook")...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	resultsCallback, err := providerutils.NewResultsCallback()
+	if err != nil {
+		diags.AddError("Failed to set up the playbook results callback", err.Error())
+
+		return nil, diags
+	}
+
+	inventoryFileNamePrefix := ".inventory-"
+	tempInventoryFiles := []string{}
+	extraVarsFile := ""
+
+	removeVaultPasswordFile := func() error { return nil }
+
+	removeTempFiles := func() {
+		for _, tempInventoryFile := range tempInventoryFiles {
+			appendSDKDiagnostics(&diags, providerutils.RemoveFile(tempInventoryFile))
+		}
+
+		if extraVarsFile != "" {
+			appendSDKDiagnostics(&diags, providerutils.RemoveFile(extraVarsFile))
+		}
+
+		err := removeVaultPasswordFile()
+		if err != nil {
+			diags.AddError("Failed to remove the vault password file", err.Error())
+		}
+	}
+
+	if len(hosts) > 0 || len(groups) > 0 {
+		tempInventoryFile, err := providerutils.BuildInventory(inventoryFileNamePrefix+"*.json", hosts, groups)
+		if err != nil {
+			diags.AddError("Failed to create the inventory", err.Error())
+		} else {
+			tempInventoryFiles = append(tempInventoryFiles, tempInventoryFile)
+		}
+	}
+
+	if !diags.HasError() && model.Inventory.ValueString() != "" {
+		tempInventoryFile, err := providerutils.WriteTempFile(
+			inventoryFileNamePrefix+"*.json",
+			[]byte(model.Inventory.ValueString()),
+		)
+		if err != nil {
+			diags.AddError("Failed to create the inventory", err.Error())
+		} else {
+			tempInventoryFiles = append(tempInventoryFiles, tempInventoryFile)
+		}
+	}
+
+	if !diags.HasError() {
+		extraVarsFile, err = providerutils.ExtraVarsFile(extraVars)
+		if err != nil {
+			diags.AddError("Failed to write the extra vars", err.Error())
+		}
+	}
+
+	// A vault password which isn't read from a file is written to a temporary password file.
+	if !diags.HasError() && vaultPassword.IsReferenced(argsTf) {
+		var vaultPasswordFile string
+
+		vaultID := r.providerConfig.PlaybookVaultID(model.VaultID.ValueStringPointer())
+
+		vaultPasswordFile, removeVaultPasswordFile, err = vaultPassword.PasswordFile(ctx, vaultID)
+		if err != nil {
+			removeVaultPasswordFile = func() error { return nil }
+
+			diags.AddError("Failed to read the vault password", redactor.Redact(err.Error()))
+		} else {
+			redactor.AddSecretFile(vaultPasswordFile)
+			argsTf = vaultPassword.ReplacePlaceholder(argsTf, vaultPasswordFile)
+		}
+	}
+
+	if diags.HasError() {
+		removeTempFiles()
+
+		err := resultsCallback.Cleanup()
+		if err != nil {
+			tflog.Warn(ctx, err.Error())
+		}
+
+		return nil, diags
+	}
+
+	tflog.Debug(ctx, "Temp Inventory Files: "+strings.Join(tempInventoryFiles, ", "))
+
+	// ********************************* RUN PLAYBOOK ********************************
+
+	args := []string{}
+
+	for _, tempInventoryFile := range tempInventoryFiles {
+		args = append(args, "-i", tempInventoryFile)
+	}
+
+	for _, inventoryFile := range r.providerConfig.PlaybookInventoryFiles(inventoryFiles) {
+		args = append(args, "-i", inventoryFile)
+	}
+
+	args = append(args, argsTf...)
+
+	// The extra vars come last, so they take precedence over the var files.
+	if extraVarsFile != "" {
+		args = append(args, "-e", "@"+extraVarsFile)
+	}
+
+	tflog.Info(ctx, fmt.Sprintf(
+		"Running Command <%s %s>",
+		ansiblePlaybookBinary,
+		strings.Join(redactor.RedactArgs(args), " "),
+	))
+
+	runAnsiblePlay := exec.CommandContext(ctx, ansiblePlaybookBinary, args...)
+	runAnsiblePlay.Env = append(os.Environ(), resultsCallback.Env()...)
+
+	runAnsiblePlayOut, runAnsiblePlayErr := runAnsiblePlay.CombinedOutput()
+
+	removeTempFiles()
+
+	// *******************************************************************************
+
+	return &playbookExecution{
+		output:          []byte(redactor.Redact(string(runAnsiblePlayOut))),
+		err:             runAnsiblePlayErr,
+		resultsCallback: resultsCallback,
+		redactor:        redactor,
+	}, diags
+}
+
+// appendSDKDiagnostics converts the SDKv2 diagnostics returned by the providerutils helpers.
+func appendSDKDiagnostics(diags *diag.Diagnostics, sdkDiags sdkdiag.Diagnostics) {
+	for _, sdkDiag := range sdkDiags {
+		if sdkDiag.Severity == sdkdiag.Error {
+			diags.AddError(sdkDiag.Summary, sdkDiag.Detail)
+		} else {
+			diags.AddWarning(sdkDiag.Summary, sdkDiag.Detail)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package framework_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/framework"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playbookResults are the attributes holding the results of the last run,
+// which the framework plans as unknown when the configuration changes.
+var playbookResults = []string{
+	"content_hashes", "ansible_playbook_stdout", "ansible_playbook_stderr",
+	"play_recap", "task_results", "outputs", "host_outputs",
+}
+
+// modifyPlaybookPlan plans the given changes of an ansible_playbook state, and returns the modified plan.
+func modifyPlaybookPlan(
+	t *testing.T,
+	state tfsdk.State,
+	changes map[string]attr.Value,
+) resource.ModifyPlanResponse {
+	t.Helper()
+
+	resp := planPlaybook(t, state, changes)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	return resp
+}
+
+// planPlaybook is modifyPlaybookPlan, without failing on errors.
+func planPlaybook(
+	t *testing.T,
+	state tfsdk.State,
+	changes map[string]attr.Value,
+) resource.ModifyPlanResponse {
+	t.Helper()
+
+	ctx := context.Background()
+
+	playbook, ok := framework.NewPlaybookResource().(resource.ResourceWithModifyPlan)
+	require.True(t, ok)
+
+	plan := tfsdk.Plan{Raw: state.Raw.Copy(), Schema: state.Schema}
+
+	for name, value := range changes {
+		diags := plan.SetAttribute(ctx, path.Root(name), value)
+		require.False(t, diags.HasError(), diags)
+	}
+
+	config := tfsdk.Config{Raw: plan.Raw.Copy(), Schema: state.Schema}
+
+	for _, name := range playbookResults {
+		attrType, diags := state.Schema.TypeAtPath(ctx, path.Root(name))
+		require.False(t, diags.HasError(), diags)
+
+		unknown, err := attrType.ValueFromTerraform(ctx, tftypes.NewValue(attrType.TerraformType(ctx), tftypes.UnknownValue))
+		require.NoError(t, err)
+
+		diags = plan.SetAttribute(ctx, path.Root(name), unknown)
+		require.False(t, diags.HasError(), diags)
+	}
+
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	playbook.ModifyPlan(ctx, resource.ModifyPlanRequest{Config: config, Plan: plan, State: state}, &resp)
+
+	return resp
+}
+
+func upgradedPlaybookState(t *testing.T) tfsdk.State {
+	t.Helper()
+
+	rawState, err := os.ReadFile(filepath.Join("testdata", "playbook_state_v0.json"))
+	require.NoError(t, err)
+
+	return upgradePlaybookState(t, 0, rawState)
+}
+
+func TestPlaybookModifyPlanUnsetSDKv2Defaults(t *testing.T) {
+	t.Parallel()
+
+	state := upgradedPlaybookState(t)
+
+	resp := modifyPlaybookPlan(t, state, map[string]attr.Value{"vault_id": types.StringNull()})
+
+	// The rendered arguments are the same, so the playbook isn't re-run.
+	assert.Equal(t, types.StringNull(), getAttribute[types.String](t, tfsdk.State(resp.Plan), "vault_id"))
+	assert.Equal(t,
+		getAttribute[types.String](t, state, "ansible_playbook_stdout"),
+		getAttribute[types.String](t, tfsdk.State(resp.Plan), "ansible_playbook_stdout"),
+	)
+	assert.Empty(t, resp.RequiresReplace)
+}
+
+func TestPlaybookModifyPlanUnsetVerbosity(t *testing.T) {
+	t.Parallel()
+
+	state := upgradedPlaybookState(t)
+
+	resp := modifyPlaybookPlan(t, state, map[string]attr.Value{"verbosity": types.Int64Null()})
+
+	// Unsetting a verbosity of 2 changes the rendered arguments, so the playbook is re-run.
+	assert.NotContains(t, getAttribute[[]string](t, tfsdk.State(resp.Plan), "args"), "-vv")
+	assert.True(t, getAttribute[types.String](t, tfsdk.State(resp.Plan), "ansible_playbook_stdout").IsUnknown())
+}
+
+func TestPlaybookTriggersRequireReplace(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	state := upgradedPlaybookState(t)
+
+	attribute, diags := state.Schema.AttributeAtPath(ctx, path.Root("triggers"))
+	require.False(t, diags.HasError(), diags)
+
+	triggers, ok := attribute.(schema.MapAttribute)
+	require.True(t, ok, "triggers is a %T", attribute)
+
+	prior := types.MapValueMust(types.StringType, map[string]attr.Value{"instance_id": types.StringValue("i-0123")})
+
+	for name, test := range map[string]struct {
+		prior   types.Map
+		planned types.Map
+		replace bool
+	}{
+		"unchanged":      {prior: prior, planned: prior, replace: false},
+		"set":            {prior: types.MapNull(types.StringType), planned: prior, replace: true},
+		"removed":        {prior: prior, planned: types.MapNull(types.StringType), replace: true},
+		"changed":        {prior: prior, planned: types.MapValueMust(types.StringType, map[string]attr.Value{"instance_id": types.StringValue("i-4567")}), replace: true},
+		"known on apply": {prior: prior, planned: types.MapUnknown(types.StringType), replace: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			plan := tfsdk.Plan{Raw: state.Raw.Copy(), Schema: state.Schema}
+
+			req := planmodifier.MapRequest{
+				Path:        path.Root("triggers"),
+				State:       state,
+				StateValue:  test.prior,
+				Plan:        plan,
+				PlanValue:   test.planned,
+				ConfigValue: test.planned,
+			}
+
+			resp := planmodifier.MapResponse{PlanValue: test.planned}
+			for _, modifier := range triggers.MapPlanModifiers() {
+				modifier.PlanModifyMap(ctx, req, &resp)
+			}
+
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+			assert.Equal(t, test.replace, resp.RequiresReplace)
+		})
+	}
+}
+
+// A change of the triggers re-runs the playbook: the results of the last run aren't kept.
+func TestPlaybookModifyPlanTriggers(t *testing.T) {
+	t.Parallel()
+
+	state := upgradedPlaybookState(t)
+
+	resp := modifyPlaybookPlan(t, state, map[string]attr.Value{
+		"triggers": types.MapValueMust(types.StringType, map[string]attr.Value{"version": types.StringValue("2")}),
+	})
+
+	assert.True(t, getAttribute[types.String](t, tfsdk.State(resp.Plan), "ansible_playbook_stdout").IsUnknown())
+	assert.True(t, getAttribute[types.List](t, tfsdk.State(resp.Plan), "task_results").IsUnknown())
+	assert.Equal(t, getAttribute[[]string](t, state, "args"), getAttribute[[]string](t, tfsdk.State(resp.Plan), "args"))
+}
+
+func TestPlaybookImportRequiresNotReplayable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	playbook, ok := framework.NewPlaybookResource().(resource.ResourceWithImportState)
+	require.True(t, ok)
+
+	schemaState := upgradedPlaybookState(t)
+
+	resp := resource.ImportStateResponse{
+		State: tfsdk.State{Raw: tftypes.NewValue(schemaState.Raw.Type(), nil), Schema: schemaState.Schema},
+	}
+	playbook.ImportState(ctx, resource.ImportStateRequest{ID: "host-1.example.com,webserver.yml"}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	assert.False(t, getAttribute[bool](t, resp.State, "replayable"))
+
+	// The schema default of 'replayable' is true.
+	planResp := planPlaybook(t, resp.State, map[string]attr.Value{"replayable": types.BoolValue(true)})
+	require.True(t, planResp.Diagnostics.HasError())
+	assert.Equal(t, "Invalid replayable", planResp.Diagnostics.Errors()[0].Summary())
+
+	planResp = planPlaybook(t, resp.State, map[string]attr.Value{"replayable": types.BoolValue(false)})
+	require.False(t, planResp.Diagnostics.HasError(), planResp.Diagnostics)
+}
+
+// withAttributes returns a copy of the state with the given attributes.
+func withAttributes(t *testing.T, state tfsdk.State, values map[string]attr.Value) tfsdk.State {
+	t.Helper()
+
+	changed := tfsdk.State{Raw: state.Raw.Copy(), Schema: state.Schema}
+
+	for name, value := range values {
+		diags := changed.SetAttribute(context.Background(), path.Root(name), value)
+		require.False(t, diags.HasError(), diags)
+	}
+
+	return changed
+}
+
+// A destroy playbook which can't run only fails the destroy without 'ignore_destroy_playbook_failure'.
+func TestPlaybookDeleteWithoutAnsiblePlaybook(t *testing.T) {
+	t.Parallel()
+
+	for _, ignoreFailure := range []bool{false, true} {
+		state := withAttributes(t, upgradedPlaybookState(t), map[string]attr.Value{
+			"replayable":                      types.BoolValue(false),
+			"destroy_playbook":                types.StringValue("teardown.yml"),
+			"ansible_playbook_binary":         types.StringValue(filepath.Join(t.TempDir(), "ansible-playbook")),
+			"ignore_destroy_playbook_failure": types.BoolValue(ignoreFailure),
+		})
+
+		resp := resource.DeleteResponse{State: state}
+		framework.NewPlaybookResource().Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+
+		assert.Equal(t, !ignoreFailure, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.NotEmpty(t, resp.Diagnostics)
+	}
+}
+
+func TestPlaybookUpdateTimeout(t *testing.T) {
+	t.Parallel()
+
+	state := upgradedPlaybookState(t)
+
+	plan := modifyPlaybookPlan(t, state, map[string]attr.Value{
+		"verbosity": types.Int64Value(1),
+		"timeouts": types.ObjectValueMust(
+			map[string]attr.Type{"create": types.StringType, "update": types.StringType},
+			map[string]attr.Value{"create": types.StringNull(), "update": types.StringValue("soon")},
+		),
+	}).Plan
+
+	resp := resource.UpdateResponse{State: state}
+	framework.NewPlaybookResource().Update(context.Background(), resource.UpdateRequest{
+		Config: tfsdk.Config(plan),
+		Plan:   plan,
+		State:  state,
+	}, &resp)
+
+	require.True(t, resp.Diagnostics.HasError())
+	assert.Equal(t, "Invalid timeout", resp.Diagnostics.Errors()[0].Summary())
+}
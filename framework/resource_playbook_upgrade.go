@@ -0,0 +1,252 @@
+package framework
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// playbookResourceModelV0 is the state of the SDKv2 ansible_playbook resource.
+type playbookResourceModelV0 struct {
+	ID                    types.String `tfsdk:"id"`
+	Playbook              types.String `tfsdk:"playbook"`
+	AnsiblePlaybookBinary types.String `tfsdk:"ansible_playbook_binary"`
+	Name                  types.String `tfsdk:"name"`
+	Groups                types.List   `tfsdk:"groups"`
+	Replayable            types.Bool   `tfsdk:"replayable"`
+	IgnorePlaybookFailure types.Bool   `tfsdk:"ignore_playbook_failure"`
+	Verbosity             types.Int64  `tfsdk:"verbosity"`
+	Tags                  types.List   `tfsdk:"tags"`
+	Limit                 types.List   `tfsdk:"limit"`
+	CheckMode             types.Bool   `tfsdk:"check_mode"`
+	DiffMode              types.Bool   `tfsdk:"diff_mode"`
+	ForceHandlers         types.Bool   `tfsdk:"force_handlers"`
+	ExtraVars             types.Map    `tfsdk:"extra_vars"`
+	VarFiles              types.List   `tfsdk:"var_files"`
+	VaultFiles            types.List   `tfsdk:"vault_files"`
+	VaultPasswordFile     types.String `tfsdk:"vault_password_file"`
+	VaultID               types.String `tfsdk:"vault_id"`
+	Args                  types.List   `tfsdk:"args"`
+	TempInventoryFile     types.String `tfsdk:"temp_inventory_file"`
+	AnsiblePlaybookStdout types.String `tfsdk:"ansible_playbook_stdout"`
+	AnsiblePlaybookStderr types.String `tfsdk:"ansible_playbook_stderr"`
+	Timeouts              types.Object `tfsdk:"timeouts"`
+}
+
+// playbookSchemaV0 is the schema of the SDKv2 ansible_playbook resource, as stored in existing states.
+// It must not change.
+func playbookSchemaV0() *schema.Schema {
+	stringList := func() schema.ListAttribute {
+		return schema.ListAttribute{ElementType: types.StringType, Optional: true}
+	}
+
+	return &schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":                      schema.StringAttribute{Computed: true},
+			"playbook":                schema.StringAttribute{Required: true},
+			"ansible_playbook_binary": schema.StringAttribute{Optional: true},
+			"name":                    schema.StringAttribute{Required: true},
+			"groups":                  stringList(),
+			"replayable":              schema.BoolAttribute{Optional: true},
+			"ignore_playbook_failure": schema.BoolAttribute{Optional: true},
+			"verbosity":               schema.Int64Attribute{Optional: true},
+			"tags":                    stringList(),
+			"limit":                   stringList(),
+			"check_mode":              schema.BoolAttribute{Optional: true},
+			"diff_mode":               schema.BoolAttribute{Optional: true},
+			"force_handlers":          schema.BoolAttribute{Optional: true},
+			"extra_vars":              schema.MapAttribute{ElementType: types.StringType, Optional: true},
+			"var_files":               stringList(),
+			"vault_files":             stringList(),
+			"vault_password_file":     schema.StringAttribute{Optional: true},
+			"vault_id":                schema.StringAttribute{Optional: true},
+			"args":                    schema.ListAttribute{ElementType: types.StringType, Computed: true},
+			"temp_inventory_file":     schema.StringAttribute{Computed: true},
+			"ansible_playbook_stdout": schema.StringAttribute{Computed: true},
+			"ansible_playbook_stderr": schema.StringAttribute{Computed: true},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": schema.SingleNestedBlock{
+				Attributes: map[string]schema.Attribute{
+					"create": schema.StringAttribute{Optional: true},
+				},
+			},
+		},
+	}
+}
+
+func (r *playbookResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   playbookSchemaV0(),
+			StateUpgrader: upgradePlaybookStateV0,
+		},
+	}
+}
+
+// upgradePlaybookStateV0 converts the SDKv2 state. The SDKv2 stored empty values for unset optional
+// settings, and the default ansible-playbook binary, which would otherwise show up as changes
+// (and re-run the playbook) on the first plan. The SDKv2 IDs were timestamps. The string 'extra_vars'
+// become an object of strings, which is what the same configuration now plans, and are removed from
+// the rendered arguments, since they are now passed in a file.
+func upgradePlaybookStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior playbookResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nullIfEmptyString := func(value types.String) types.String {
+		if value.ValueString() == "" {
+			return types.StringNull()
+		}
+
+		return value
+	}
+	nullIfEmptyList := func(value types.List) types.List {
+		if len(value.Elements()) == 0 {
+			return types.ListNull(types.StringType)
+		}
+
+		return value
+	}
+
+	// Older versions stored the default value in state.
+	if prior.AnsiblePlaybookBinary.ValueString() == providerutils.DefaultAnsiblePlaybookBinary {
+		prior.AnsiblePlaybookBinary = types.StringNull()
+	}
+
+	// Settings added after the resource was created are null in its state.
+	if prior.Replayable.IsNull() {
+		prior.Replayable = types.BoolValue(true)
+	}
+
+	extraVars := types.DynamicNull()
+
+	if len(prior.ExtraVars.Elements()) > 0 {
+		attrTypes := map[string]attr.Type{}
+		for key := range prior.ExtraVars.Elements() {
+			attrTypes[key] = types.StringType
+		}
+
+		object, diags := types.ObjectValue(attrTypes, prior.ExtraVars.Elements())
+		resp.Diagnostics.Append(diags...)
+
+		extraVars = types.DynamicValue(object)
+	}
+
+	// The 'update' timeout is new.
+	timeouts := types.ObjectNull(timeoutsAttrTypes)
+
+	if !prior.Timeouts.IsNull() {
+		var diags diag.Diagnostics
+
+		timeouts, diags = types.ObjectValue(timeoutsAttrTypes, map[string]attr.Value{
+			"create": prior.Timeouts.Attributes()["create"],
+			"update": types.StringNull(),
+		})
+		resp.Diagnostics.Append(diags...)
+	}
+
+	args := prior.Args
+
+	if !prior.Args.IsNull() {
+		var priorArgs []string
+
+		resp.Diagnostics.Append(prior.Args.ElementsAs(ctx, &priorArgs, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		var diags diag.Diagnostics
+
+		args, diags = types.ListValueFrom(ctx, types.StringType, upgradeExtraVarsArgs(priorArgs))
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The SDKv2 stored 0 and "" when 'verbosity', 'vault_password_file' and 'vault_id' weren't set, which
+	// can't be told apart from set values, so they are kept. Unsetting them doesn't re-run the playbook,
+	// see keepsLastRun.
+	current := playbookResourceModel{
+		ID:                           types.StringValue(playbookID(prior.Name.ValueString(), prior.Playbook.ValueString())),
+		Playbook:                     prior.Playbook,
+		AnsiblePlaybookBinary:        nullIfEmptyString(prior.AnsiblePlaybookBinary),
+		Name:                         prior.Name,
+		Groups:                       nullIfEmptyList(prior.Groups),
+		Hosts:                        types.DynamicNull(),
+		InventoryGroups:              types.DynamicNull(),
+		Inventory:                    types.StringNull(),
+		InventoryFiles:               types.ListNull(types.StringType),
+		Replayable:                   prior.Replayable,
+		Triggers:                     types.MapNull(types.StringType),
+		IgnorePlaybookFailure:        types.BoolValue(prior.IgnorePlaybookFailure.ValueBool()),
+		Verbosity:                    types.Int64Value(prior.Verbosity.ValueInt64()),
+		Tags:                         nullIfEmptyList(prior.Tags),
+		Limit:                        nullIfEmptyList(prior.Limit),
+		CheckMode:                    types.BoolValue(prior.CheckMode.ValueBool()),
+		DiffMode:                     types.BoolValue(prior.DiffMode.ValueBool()),
+		ForceHandlers:                types.BoolValue(prior.ForceHandlers.ValueBool()),
+		User:                         types.StringNull(),
+		PrivateKeyFile:               types.StringNull(),
+		ExtraVars:                    extraVars,
+		SensitiveExtraVars:           types.MapNull(types.StringType),
+		VarFiles:                     nullIfEmptyList(prior.VarFiles),
+		VaultFiles:                   nullIfEmptyList(prior.VaultFiles),
+		VaultPasswordFile:            types.StringValue(prior.VaultPasswordFile.ValueString()),
+		VaultPassword:                types.StringNull(),
+		VaultPasswordCommand:         types.ListNull(types.StringType),
+		VaultID:                      types.StringValue(prior.VaultID.ValueString()),
+		ReplayOnContentChange:        types.BoolValue(false),
+		CaptureOutputs:               types.BoolValue(false),
+		DestroyPlaybook:              types.StringNull(),
+		DestroyExtraVars:             types.MapNull(types.StringType),
+		DestroyTags:                  types.ListNull(types.StringType),
+		IgnoreDestroyPlaybookFailure: types.BoolValue(false),
+		Args:                         args,
+		ContentHashes:                types.MapNull(types.StringType),
+		TempInventoryFile:            prior.TempInventoryFile,
+		AnsiblePlaybookStdout:        prior.AnsiblePlaybookStdout,
+		AnsiblePlaybookStderr:        prior.AnsiblePlaybookStderr,
+		PlayRecap:                    types.ListNull(types.ObjectType{AttrTypes: playRecapAttrTypes}),
+		Outputs:                      types.MapNull(types.StringType),
+		HostOutputs:                  types.MapNull(types.StringType),
+		TaskResults:                  types.ListNull(types.ObjectType{AttrTypes: taskResultAttrTypes}),
+		Timeouts:                     timeouts,
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &current)...)
+}
+
+// upgradeExtraVarsArgs removes the extra vars rendered by the SDKv2 implementation, an "-e key='value'"
+// argument per variable before the playbook, which are now passed in a file.
+func upgradeExtraVarsArgs(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+
+	end := len(args) - 1
+	start := end
+
+	for start >= 2 && args[start-2] == "-e" {
+		_, value, found := strings.Cut(args[start-1], "='")
+		if !found || !strings.HasSuffix(value, "'") {
+			break
+		}
+
+		start -= 2
+	}
+
+	return slices.Concat(args[:start], args[end:])
+}
@@ -0,0 +1,158 @@
+package framework_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/framework"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upgradePlaybookState upgrades a raw ansible_playbook state of the given schema version to the current schema.
+func upgradePlaybookState(t *testing.T, version int64, rawState []byte) tfsdk.State {
+	t.Helper()
+
+	ctx := context.Background()
+
+	playbook, ok := framework.NewPlaybookResource().(resource.ResourceWithUpgradeState)
+	require.True(t, ok)
+
+	upgrader, ok := playbook.UpgradeState(ctx)[version]
+	require.True(t, ok, "no upgrader from version %d", version)
+
+	prior, err := tftypes.ValueFromJSON(rawState, upgrader.PriorSchema.Type().TerraformType(ctx))
+	require.NoError(t, err)
+
+	var schemaResp resource.SchemaResponse
+	playbook.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	resp := resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	upgrader.StateUpgrader(ctx, resource.UpgradeStateRequest{
+		State: &tfsdk.State{Raw: prior, Schema: *upgrader.PriorSchema},
+	}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	return resp.State
+}
+
+func getAttribute[T any](t *testing.T, state tfsdk.State, name string) T {
+	t.Helper()
+
+	var value T
+
+	diags := state.GetAttribute(context.Background(), path.Root(name), &value)
+	require.False(t, diags.HasError(), diags)
+
+	return value
+}
+
+// The fixture is the state of a resource created by the SDKv2 implementation.
+func TestUpgradePlaybookStateV0(t *testing.T) {
+	t.Parallel()
+
+	rawState, err := os.ReadFile(filepath.Join("testdata", "playbook_state_v0.json"))
+	require.NoError(t, err)
+
+	state := upgradePlaybookState(t, 0, rawState)
+
+	assert.Equal(t, "web-1,site.yml", getAttribute[string](t, state, "id"))
+	assert.Equal(t, types.StringNull(), getAttribute[types.String](t, state, "ansible_playbook_binary"))
+	assert.Equal(t, []string{"web"}, getAttribute[[]string](t, state, "groups"))
+	assert.False(t, getAttribute[bool](t, state, "replayable"))
+	assert.Equal(t, int64(2), getAttribute[int64](t, state, "verbosity"))
+	assert.Equal(t, types.ListNull(types.StringType), getAttribute[types.List](t, state, "limit"))
+	assert.Equal(t, "vault-password.txt", getAttribute[string](t, state, "vault_password_file"))
+
+	// The extra vars are passed in a file, so they are removed from the arguments.
+	assert.Equal(t, []string{
+		"-vv", "-e", "hostname=web-1", "--tags", "deploy", "-e", "@vars.yml", "-e", "@vault.yml",
+		"--vault-id", "@vault-password.txt", "site.yml",
+	}, getAttribute[[]string](t, state, "args"))
+
+	extraVars := getAttribute[types.Dynamic](t, state, "extra_vars")
+	object, ok := extraVars.UnderlyingValue().(types.Object)
+	require.True(t, ok, "extra_vars is a %T", extraVars.UnderlyingValue())
+	assert.Equal(t, map[string]string{"ansible_user": "deploy", "http_port": "8080"}, stringAttributes(t, object))
+
+	assert.Equal(t, types.MapNull(types.StringType), getAttribute[types.Map](t, state, "content_hashes"))
+	assert.False(t, getAttribute[bool](t, state, "replay_on_content_change"))
+	assert.Equal(t, types.StringNull(), getAttribute[types.String](t, state, "destroy_playbook"))
+}
+
+func TestUpgradePlaybookStateV0Defaults(t *testing.T) {
+	t.Parallel()
+
+	state := upgradePlaybookState(t, 0, []byte(`{
+		"ansible_playbook_binary": "ansible-playbook",
+		"ansible_playbook_stderr": "",
+		"ansible_playbook_stdout": "",
+		"args": ["-e", "hostname=localhost", "play.yml"],
+		"check_mode": false,
+		"diff_mode": false,
+		"extra_vars": null,
+		"force_handlers": false,
+		"groups": null,
+		"id": "2024-03-18 09:41:27.512873004 +0000 UTC m=+1.284017421",
+		"ignore_playbook_failure": false,
+		"limit": [],
+		"name": "localhost",
+		"playbook": "play.yml",
+		"replayable": true,
+		"tags": null,
+		"temp_inventory_file": "",
+		"timeouts": {"create": "10m"},
+		"var_files": null,
+		"vault_files": null,
+		"vault_id": "",
+		"vault_password_file": "",
+		"verbosity": 0
+	}`))
+
+	assert.Equal(t, "localhost,play.yml", getAttribute[string](t, state, "id"))
+	assert.Equal(t, types.StringNull(), getAttribute[types.String](t, state, "ansible_playbook_binary"))
+	assert.Equal(t, types.ListNull(types.StringType), getAttribute[types.List](t, state, "limit"))
+	assert.Equal(t, types.DynamicNull(), getAttribute[types.Dynamic](t, state, "extra_vars"))
+	assert.Equal(t, []string{"-e", "hostname=localhost", "play.yml"}, getAttribute[[]string](t, state, "args"))
+	assert.True(t, getAttribute[bool](t, state, "replayable"))
+
+	// The SDKv2 stored these when they weren't set, they can't be told apart from set values.
+	assert.Equal(t, types.Int64Value(0), getAttribute[types.Int64](t, state, "verbosity"))
+	assert.Equal(t, types.StringValue(""), getAttribute[types.String](t, state, "vault_password_file"))
+	assert.Equal(t, types.StringValue(""), getAttribute[types.String](t, state, "vault_id"))
+	assert.Equal(t, types.StringNull(), getAttribute[types.String](t, state, "user"))
+
+	var timeout string
+
+	diags := state.GetAttribute(context.Background(), path.Root("timeouts").AtName("create"), &timeout)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "10m", timeout)
+
+	var updateTimeout types.String
+
+	diags = state.GetAttribute(context.Background(), path.Root("timeouts").AtName("update"), &updateTimeout)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, types.StringNull(), updateTimeout)
+}
+
+func stringAttributes(t *testing.T, object types.Object) map[string]string {
+	t.Helper()
+
+	values := map[string]string{}
+
+	for key, value := range object.Attributes() {
+		str, ok := value.(types.String)
+		require.True(t, ok, "%s is a %T", key, value)
+
+		values[key] = str.ValueString()
+	}
+
+	return values
+}
@@ -0,0 +1,566 @@
+package framework
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.ResourceWithConfigure      = (*vaultEncryptedResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*vaultEncryptedResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*vaultEncryptedResource)(nil)
+)
+
+var errInvalidFilePermission = errors.New("invalid file permission")
+
+// defaultVaultFilePermission is the permission of vault files when `file_permission` isn't set.
+const defaultVaultFilePermission = "0600"
+
+// maxFilePermission is the highest file permission, without the setuid, setgid and sticky bits.
+const maxFilePermission = 0o777
+
+// vaultEncryptedResource encrypts content into a vault file, like 'ansible-vault encrypt'.
+type vaultEncryptedResource struct {
+	providerConfig *providerutils.ProviderConfig
+}
+
+func NewVaultEncryptedResource() resource.Resource {
+	return &vaultEncryptedResource{}
+}
+
+func (r *vaultEncryptedResource) Configure(
+	ctx context.Context,
+	req resource.ConfigureRequest,
+	resp *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerConfig, ok := req.ProviderData.(*providerutils.ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *providerutils.ProviderConfig, got %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.providerConfig = providerConfig
+}
+
+func (r *vaultEncryptedResource) Metadata(
+	ctx context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_vault_encrypted"
+}
+
+type vaultEncryptedResourceModel struct {
+	ID                   types.String  `tfsdk:"id"`
+	VaultFile            types.String  `tfsdk:"vault_file"`
+	Content              types.String  `tfsdk:"content"`
+	Data                 types.Dynamic `tfsdk:"data"`
+	VaultID              types.String  `tfsdk:"vault_id"`
+	VaultPasswordFile    types.String  `tfsdk:"vault_password_file"`
+	VaultPassword        types.String  `tfsdk:"vault_password"`
+	VaultPasswordCommand types.List    `tfsdk:"vault_password_command"`
+	VaultPasswordVersion types.String  `tfsdk:"vault_password_version"`
+	FilePermission       types.String  `tfsdk:"file_permission"`
+	ContentSHA256        types.String  `tfsdk:"content_sha256"`
+	VaultSHA256          types.String  `tfsdk:"vault_sha256"`
+}
+
+func (r *vaultEncryptedResource) Schema(
+	ctx context.Context,
+	req resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Encrypts content into an Ansible Vault file, like `ansible-vault encrypt`. " +
+			"The file is only encrypted again when the content, the vault ID or the vault password changes.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The path of the vault file.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"vault_file": schema.StringAttribute{
+				MarkdownDescription: "Path of the vault file to write. Its directory is created if needed.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "The content to encrypt. Conflicts with `data`. " +
+					"It isn't stored in the state, only its digest in `content_sha256`.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+			"data": schema.DynamicAttribute{
+				MarkdownDescription: "An object or a map of variables to encrypt, rendered to YAML with sorted keys. " +
+					"Conflicts with `content`. It isn't stored in the state, only its digest in `content_sha256`.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+			"vault_id": schema.StringAttribute{
+				MarkdownDescription: "The vault ID written in the vault header, and given to vault password client scripts. " +
+					"Vaults without a vault ID use the `1.1` format.",
+				Optional: true,
+			},
+			"vault_password_file": schema.StringAttribute{
+				MarkdownDescription: "Path to vault password file, or to an executable script printing the password. " +
+					"Defaults to the provider's `vault_password_file`, unless `vault_password` or `vault_password_command` is set.",
+				Optional: true,
+			},
+			"vault_password": schema.StringAttribute{
+				MarkdownDescription: "The vault password. It isn't stored in the state: change `vault_password_version` " +
+					"to encrypt the file again with a new password.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+			"vault_password_command": schema.ListAttribute{
+				MarkdownDescription: "Command printing the vault password, and its arguments, e.g. `[\"pass\", \"ansible/vault\"]`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"vault_password_version": schema.StringAttribute{
+				MarkdownDescription: "Any value, changed to encrypt the file again when the vault password changes: " +
+					"the password itself isn't compared, it isn't stored in the state.",
+				Optional: true,
+			},
+			"file_permission": schema.StringAttribute{
+				MarkdownDescription: "Permission of the vault file, in octal. Defaults to `" + defaultVaultFilePermission + "`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(defaultVaultFilePermission),
+			},
+			"content_sha256": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 digest of the plaintext, `content` or `data` rendered to YAML, " +
+					"used to detect changes of the write-only content.",
+				Computed: true,
+			},
+			"vault_sha256": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 digest of the vault file, used to detect changes made outside of Terraform: " +
+					"the file is then written again.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (r *vaultEncryptedResource) ValidateConfig(
+	ctx context.Context,
+	req resource.ValidateConfigRequest,
+	resp *resource.ValidateConfigResponse,
+) {
+	var config vaultEncryptedResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Content.IsNull() == config.Data.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Invalid vault content",
+			"Exactly one of content or data must be set.",
+		)
+	}
+
+	_, err := decodeVars(ctx, config.Data)
+	if err != nil && !errors.Is(err, errUnknownValue) {
+		resp.Diagnostics.AddAttributeError(path.Root("data"), "Invalid data", err.Error())
+	}
+
+	if !config.VaultPasswordFile.IsNull() && !config.VaultPassword.IsNull() ||
+		!config.VaultPasswordCommand.IsNull() && (!config.VaultPasswordFile.IsNull() || !config.VaultPassword.IsNull()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("vault_password"),
+			"Conflicting vault passwords",
+			"Only one of vault_password_file, vault_password or vault_password_command can be set.",
+		)
+	}
+
+	if !config.FilePermission.IsUnknown() && !config.FilePermission.IsNull() {
+		_, err := fileMode(config.FilePermission.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("file_permission"), "Invalid file permission", err.Error())
+		}
+	}
+}
+
+// fileMode parses an octal file permission, such as "0640".
+func fileMode(permission string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(permission, 8, 32)
+	if err != nil || mode > maxFilePermission {
+		return 0, fmt.Errorf(
+			"%w %q, expected an octal permission such as %q", errInvalidFilePermission, permission, defaultVaultFilePermission,
+		)
+	}
+
+	return os.FileMode(mode), nil
+}
+
+// plaintext is the content to encrypt: 'content', or 'data' rendered to YAML.
+func (m *vaultEncryptedResourceModel) plaintext(ctx context.Context) (string, error) {
+	if !m.Content.IsNull() {
+		if m.Content.IsUnknown() {
+			return "", errUnknownValue
+		}
+
+		return m.Content.ValueString(), nil
+	}
+
+	vars, err := decodeVars(ctx, m.Data)
+	if err != nil {
+		return "", err
+	}
+
+	return providerutils.VaultYAML(vars)
+}
+
+// readContent reads the write-only 'content' and 'data' from the configuration.
+func (m *vaultEncryptedResourceModel) readContent(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	diags := config.GetAttribute(ctx, path.Root("content"), &m.Content)
+	diags.Append(config.GetAttribute(ctx, path.Root("data"), &m.Data)...)
+
+	return diags
+}
+
+// contentSHA256 is the digest of the plaintext, unknown until the content is known.
+func (m *vaultEncryptedResourceModel) contentSHA256(ctx context.Context) (types.String, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	plaintext, err := m.plaintext(ctx)
+	if errors.Is(err, errUnknownValue) {
+		return types.StringUnknown(), diags
+	}
+
+	if err != nil {
+		diags.AddAttributeError(path.Root("data"), "Invalid data", err.Error())
+
+		return types.StringNull(), diags
+	}
+
+	digest := sha256.Sum256([]byte(plaintext))
+
+	return types.StringValue(hex.EncodeToString(digest[:])), diags
+}
+
+// vaultPassword is the source of the vault password: 'vault_password', which is write-only and must be read
+// from the configuration, 'vault_password_command', or the vault password file, defaulting to the provider's.
+func (m *vaultEncryptedResourceModel) vaultPassword(
+	ctx context.Context,
+	providerConfig *providerutils.ProviderConfig,
+) (providerutils.VaultPassword, diag.Diagnostics) {
+	vaultPassword := providerutils.VaultPassword{Password: m.VaultPassword.ValueString()}
+
+	diags := m.VaultPasswordCommand.ElementsAs(ctx, &vaultPassword.Command, false)
+
+	if !vaultPassword.IsSet() {
+		vaultPassword.File = providerConfig.PlaybookVaultPasswordFile(m.VaultPasswordFile.ValueStringPointer())
+	}
+
+	return vaultPassword, diags
+}
+
+// needsEncryption reports whether the vault file must be encrypted again: when the plaintext, the vault ID
+// or the vault password changed. Changes of the content which render the same plaintext, such as moving
+// variables from 'content' to 'data', don't encrypt the file again.
+func (m *vaultEncryptedResourceModel) needsEncryption(state *vaultEncryptedResourceModel) bool {
+	return m.ContentSHA256.IsUnknown() ||
+		!m.ContentSHA256.Equal(state.ContentSHA256) ||
+		!m.VaultID.Equal(state.VaultID) ||
+		!m.VaultPasswordFile.Equal(state.VaultPasswordFile) ||
+		!m.VaultPasswordCommand.Equal(state.VaultPasswordCommand) ||
+		!m.VaultPasswordVersion.Equal(state.VaultPasswordVersion)
+}
+
+func (r *vaultEncryptedResource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	// Nothing to do on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan vaultEncryptedResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The write-only content is only in the configuration, and mustn't be planned.
+	contentModel := plan
+
+	resp.Diagnostics.Append(contentModel.readContent(ctx, req.Config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var diags diag.Diagnostics
+
+	plan.ContentSHA256, diags = contentModel.contentSHA256(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state vaultEncryptedResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// The digest only changes if the file is encrypted again.
+		if !plan.needsEncryption(&state) {
+			plan.VaultSHA256 = state.VaultSHA256
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *vaultEncryptedResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan vaultEncryptedResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	// The write-only content and vault_password are removed from the state by the framework.
+	resp.Diagnostics.Append(plan.readContent(ctx, req.Config)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("vault_password"), &plan.VaultPassword)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The content might only be known now.
+	var diags diag.Diagnostics
+
+	plan.ContentSHA256, diags = plan.contentSHA256(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.encrypt(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *vaultEncryptedResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state vaultEncryptedResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vaultFile := state.VaultFile.ValueString()
+
+	digest, err := fileSHA256(vaultFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		tflog.Info(ctx, "Vault file was removed outside of Terraform", map[string]any{"vault_file": vaultFile})
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("vault_file"), "Could not read the vault file", err.Error())
+		return
+	}
+
+	// The vault file was modified outside of Terraform, it's written again.
+	if digest != state.VaultSHA256.ValueString() {
+		tflog.Info(ctx, "Vault file was modified outside of Terraform", map[string]any{"vault_file": vaultFile})
+		resp.State.RemoveResource(ctx)
+	}
+}
+
+func (r *vaultEncryptedResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state vaultEncryptedResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	// The write-only content and vault_password are removed from the state by the framework.
+	resp.Diagnostics.Append(plan.readContent(ctx, req.Config)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("vault_password"), &plan.VaultPassword)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The content might only be known now.
+	var diags diag.Diagnostics
+
+	plan.ContentSHA256, diags = plan.contentSHA256(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.needsEncryption(&state) {
+		resp.Diagnostics.Append(r.encrypt(ctx, &plan)...)
+	} else {
+		plan.VaultSHA256 = state.VaultSHA256
+
+		resp.Diagnostics.Append(plan.chmod()...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *vaultEncryptedResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state vaultEncryptedResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := os.Remove(state.VaultFile.ValueString())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		resp.Diagnostics.AddAttributeError(path.Root("vault_file"), "Could not remove the vault file", err.Error())
+	}
+}
+
+// encrypt encrypts the plaintext and writes the vault file.
+func (r *vaultEncryptedResource) encrypt(ctx context.Context, plan *vaultEncryptedResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	plaintext, err := plan.plaintext(ctx)
+	if err != nil {
+		diags.AddAttributeError(path.Root("data"), "Invalid data", err.Error())
+		return diags
+	}
+
+	permission, err := fileMode(plan.FilePermission.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("file_permission"), "Invalid file permission", err.Error())
+		return diags
+	}
+
+	password, passwordDiags := plan.vaultPassword(ctx, r.providerConfig)
+	diags.Append(passwordDiags...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	if !password.IsSet() {
+		diags.AddAttributeError(
+			path.Root("vault_password_file"),
+			"Missing vault password",
+			"One of vault_password_file, vault_password or vault_password_command must be set, "+
+				"or the provider's vault_password_file.",
+		)
+
+		return diags
+	}
+
+	vaultID := plan.VaultID.ValueString()
+
+	passwordBytes, err := password.Read(ctx, vaultID)
+	if err != nil {
+		// The vault password is masked in the error output.
+		redactor := providerutils.NewRedactor()
+		redactor.AddSecretFile(password.File)
+		redactor.AddSecrets(password.Password)
+
+		diags.AddAttributeError(
+			path.Root("vault_password_file"),
+			"Could not read the vault password",
+			redactor.Redact(err.Error()),
+		)
+
+		return diags
+	}
+
+	vault, err := providerutils.EncryptVault([]byte(plaintext), passwordBytes, vaultID)
+	if err != nil {
+		diags.AddError("Could not encrypt the vault", err.Error())
+		return diags
+	}
+
+	err = providerutils.WriteVaultFile(plan.VaultFile.ValueString(), vault, permission)
+	if err != nil {
+		diags.AddAttributeError(path.Root("vault_file"), "Could not write the vault file", err.Error())
+		return diags
+	}
+
+	digest := sha256.Sum256(vault)
+
+	plan.ID = plan.VaultFile
+	plan.VaultSHA256 = types.StringValue(hex.EncodeToString(digest[:]))
+
+	return diags
+}
+
+// chmod applies 'file_permission' to the vault file, when it isn't encrypted again.
+func (m *vaultEncryptedResourceModel) chmod() diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	permission, err := fileMode(m.FilePermission.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("file_permission"), "Invalid file permission", err.Error())
+		return diags
+	}
+
+	err = os.Chmod(m.VaultFile.ValueString(), permission)
+	if err != nil {
+		diags.AddAttributeError(path.Root("vault_file"), "Could not change the vault file permission", err.Error())
+	}
+
+	return diags
+}
+
+// fileSHA256 is the hex encoded SHA-256 digest of a file.
+func fileSHA256(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("couldn't read %s: %w", file, err)
+	}
+
+	digest := sha256.Sum256(content)
+
+	return hex.EncodeToString(digest[:]), nil
+}
@@ -0,0 +1,189 @@
+package framework_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/framework"
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const vaultEncryptedPassword = "vault-password"
+
+func vaultEncryptedSchema(t *testing.T) resource.SchemaResponse {
+	t.Helper()
+
+	var schemaResp resource.SchemaResponse
+	framework.NewVaultEncryptedResource().Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError(), schemaResp.Diagnostics)
+
+	return schemaResp
+}
+
+// vaultEncryptedConfig is an ansible_vault_encrypted configuration, and the plan Terraform proposes for it:
+// without the write-only attributes, and with the default permission and unknown computed attributes.
+func vaultEncryptedConfig(t *testing.T, values map[string]any) (tfsdk.Config, tfsdk.Plan) {
+	t.Helper()
+
+	ctx := context.Background()
+	schemaResp := vaultEncryptedSchema(t)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	config := tfsdk.Config{Raw: terraformValue(t, objectType, values), Schema: schemaResp.Schema}
+
+	planned := map[string]any{"file_permission": "0600"}
+	for name, value := range values {
+		planned[name] = value
+	}
+
+	for _, name := range []string{"content", "data", "vault_password"} {
+		delete(planned, name)
+	}
+
+	for _, name := range []string{"id", "content_sha256", "vault_sha256"} {
+		planned[name] = tftypes.UnknownValue
+	}
+
+	return config, tfsdk.Plan{Raw: terraformValue(t, objectType, planned), Schema: schemaResp.Schema}
+}
+
+func modifyVaultEncryptedPlan(t *testing.T, values map[string]any, state tfsdk.State) tfsdk.Plan {
+	t.Helper()
+
+	vault, ok := framework.NewVaultEncryptedResource().(resource.ResourceWithModifyPlan)
+	require.True(t, ok)
+
+	config, plan := vaultEncryptedConfig(t, values)
+
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	vault.ModifyPlan(context.Background(), resource.ModifyPlanRequest{Config: config, Plan: plan, State: state}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	return resp.Plan
+}
+
+// createVaultEncrypted plans and applies a new ansible_vault_encrypted resource.
+func createVaultEncrypted(t *testing.T, values map[string]any) tfsdk.State {
+	t.Helper()
+
+	ctx := context.Background()
+	schemaResp := vaultEncryptedSchema(t)
+	emptyState := tfsdk.State{Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil), Schema: schemaResp.Schema}
+
+	plan := modifyVaultEncryptedPlan(t, values, emptyState)
+	config, _ := vaultEncryptedConfig(t, values)
+
+	resp := resource.CreateResponse{State: emptyState}
+	framework.NewVaultEncryptedResource().Create(ctx, resource.CreateRequest{Config: config, Plan: plan}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	return resp.State
+}
+
+func sha256Hex(content string) string {
+	digest := sha256.Sum256([]byte(content))
+
+	return hex.EncodeToString(digest[:])
+}
+
+func TestVaultEncryptedCreate(t *testing.T) {
+	t.Parallel()
+
+	vaultFile := filepath.Join(t.TempDir(), "vault.yml")
+
+	state := createVaultEncrypted(t, map[string]any{
+		"vault_file":     vaultFile,
+		"content":        "db_password: s3cr3t\n",
+		"vault_password": vaultEncryptedPassword,
+	})
+
+	// The content is only stored as a digest.
+	assert.Equal(t, sha256Hex("db_password: s3cr3t\n"), getAttribute[string](t, state, "content_sha256"))
+
+	vault, err := os.ReadFile(vaultFile)
+	require.NoError(t, err)
+	assert.Equal(t, sha256Hex(string(vault)), getAttribute[string](t, state, "vault_sha256"))
+
+	plaintext, err := providerutils.DecryptVault(vault, []byte(vaultEncryptedPassword))
+	require.NoError(t, err)
+	assert.Equal(t, "db_password: s3cr3t\n", string(plaintext))
+}
+
+// The vault file is only encrypted again when the plaintext, the vault ID or the vault password changes.
+func TestVaultEncryptedNeedsEncryption(t *testing.T) {
+	t.Parallel()
+
+	vaultFile := filepath.Join(t.TempDir(), "vault.yml")
+	values := map[string]any{
+		"vault_file":             vaultFile,
+		"content":                "db_password: s3cr3t\n",
+		"vault_password":         vaultEncryptedPassword,
+		"vault_password_version": "1",
+	}
+
+	state := createVaultEncrypted(t, values)
+	vaultSHA256 := getAttribute[string](t, state, "vault_sha256")
+
+	for name, test := range map[string]struct {
+		changes map[string]any
+		encrypt bool
+	}{
+		"unchanged":           {changes: map[string]any{}, encrypt: false},
+		"same plaintext":      {changes: map[string]any{"content": nil, "data": dynamicData(t)}, encrypt: false},
+		"file permission":     {changes: map[string]any{"file_permission": "0640"}, encrypt: false},
+		"new password":        {changes: map[string]any{"vault_password": "other-password"}, encrypt: false},
+		"content":             {changes: map[string]any{"content": "db_password: changed\n"}, encrypt: true},
+		"vault ID":            {changes: map[string]any{"vault_id": "prod"}, encrypt: true},
+		"password version":    {changes: map[string]any{"vault_password_version": "2"}, encrypt: true},
+		"password file":       {changes: map[string]any{"vault_password": nil, "vault_password_file": "pw.txt"}, encrypt: true},
+		"content not known":   {changes: map[string]any{"content": tftypes.UnknownValue}, encrypt: true},
+		"password command":    {changes: map[string]any{"vault_password": nil, "vault_password_command": []any{"pass"}}, encrypt: true},
+		"content is now data": {changes: map[string]any{"content": nil, "data": dynamicData(t, "other")}, encrypt: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			changed := map[string]any{}
+			for key, value := range values {
+				changed[key] = value
+			}
+
+			for key, value := range test.changes {
+				changed[key] = value
+			}
+
+			plan := modifyVaultEncryptedPlan(t, changed, state)
+			planned := getAttribute[types.String](t, tfsdk.State(plan), "vault_sha256")
+
+			if test.encrypt {
+				assert.True(t, planned.IsUnknown(), planned)
+			} else {
+				assert.Equal(t, types.StringValue(vaultSHA256), planned)
+			}
+		})
+	}
+}
+
+// dynamicData is a 'data' value whose YAML rendering is the content of the test vault, or another password.
+func dynamicData(t *testing.T, password ...string) tftypes.Value {
+	t.Helper()
+
+	value := "s3cr3t"
+	if len(password) > 0 {
+		value = password[0]
+	}
+
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"db_password": tftypes.String}}
+
+	return tftypes.NewValue(objectType, map[string]tftypes.Value{"db_password": tftypes.NewValue(tftypes.String, value)})
+}
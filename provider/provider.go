@@ -1,17 +1,197 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Provider exported function.
+//
+// NOTE: the provider schema must stay identical to the one declared in framework/provider.go,
+// since both servers are muxed together.
 func Provider() *schema.Provider {
 	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"ansible_playbook_binary": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Default path to ansible-playbook executable (binary).",
+			},
+
+			"ansible_vault_binary": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Default path to ansible-vault executable (binary).",
+			},
+
+			"ansible_inventory_binary": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Default path to ansible-inventory executable (binary).",
+			},
+
+			"inventory_files": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Default list of inventory files used by every playbook run.",
+			},
+
+			"extra_vars": {
+				Type:     schema.TypeMap,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				Description: "Default map of additional variables passed to every playbook run. " +
+					"Variables set on a resource or action take precedence.",
+			},
+
+			"extra_vars_json": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateJSONObject),
+				Description: "Default variables as a JSON object, e.g. from jsonencode(), for values which are not " +
+					"strings (lists, objects, numbers, booleans). Merged on top of 'extra_vars'.",
+			},
+
+			"vault_password_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Default path to a vault password file.",
+			},
+
+			"vault_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Default ID of the desired vault(s).",
+			},
+
+			"user": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Default user to connect as.",
+			},
+
+			"private_key_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Default path to the private key file used for connections.",
+			},
+
+			"verbosity": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Default verbosity level between 0 and 6.",
+			},
+		},
 		ResourcesMap: map[string]*schema.Resource{
-			"ansible_playbook": resourcePlaybook(),
-			"ansible_vault":    resourceVault(),
-			"ansible_host":     resourceHost(),
-			"ansible_group":    resourceGroup(),
+			"ansible_vault": resourceVault(),
+			"ansible_host":  resourceHost(),
+			"ansible_group": resourceGroup(),
 		},
+		ConfigureContextFunc: providerConfigure,
+	}
+}
+
+func providerConfigure(_ context.Context, data *schema.ResourceData) (any, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	config := &providerutils.ProviderConfig{
+		ExtraVars: map[string]any{},
+	}
+
+	stringSettings := map[string]*string{
+		"ansible_playbook_binary":  &config.AnsiblePlaybookBinary,
+		"ansible_vault_binary":     &config.AnsibleVaultBinary,
+		"ansible_inventory_binary": &config.AnsibleInventoryBinary,
+		"vault_password_file":      &config.VaultPasswordFile,
+		"vault_id":                 &config.VaultID,
+		"user":                     &config.User,
+		"private_key_file":         &config.PrivateKeyFile,
 	}
+
+	for key, dest := range stringSettings {
+		value, okay := data.Get(key).(string)
+		if !okay {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("ERROR [ansible]: couldn't get '%s'!", key),
+			})
+		}
+
+		*dest = value
+	}
+
+	verbosity, okay := data.Get("verbosity").(int)
+	if !okay {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "ERROR [ansible]: couldn't get 'verbosity'!",
+		})
+	}
+
+	config.Verbosity = verbosity
+
+	inventoryFiles, okay := data.Get("inventory_files").([]any)
+	if !okay {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "ERROR [ansible]: couldn't get 'inventory_files'!",
+		})
+	}
+
+	var diagsFromUtils diag.Diagnostics
+
+	config.InventoryFiles, diagsFromUtils = providerutils.InterfaceToString(inventoryFiles)
+	diags = append(diags, diagsFromUtils...)
+
+	extraVars, okay := data.Get("extra_vars").(map[string]any)
+	if !okay {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "ERROR [ansible]: couldn't get 'extra_vars'!",
+		})
+	}
+
+	for key, val := range extraVars {
+		tmpVal, okay := val.(string)
+		if !okay {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "ERROR [ansible]: couldn't assert type: string",
+			})
+		}
+
+		config.ExtraVars[key] = tmpVal
+	}
+
+	extraVarsJSON, okay := data.Get("extra_vars_json").(string)
+	if !okay {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "ERROR [ansible]: couldn't get 'extra_vars_json'!",
+		})
+	}
+
+	if extraVarsJSON != "" {
+		decoder := json.NewDecoder(strings.NewReader(extraVarsJSON))
+		decoder.UseNumber()
+
+		err := decoder.Decode(&config.ExtraVars)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "ERROR [ansible]: couldn't decode 'extra_vars_json'!",
+				Detail:   err.Error(),
+			})
+		}
+	}
+
+	return config, diags
 }
@@ -0,0 +1,62 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/provider"
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, provider.Provider().InternalValidate())
+}
+
+func TestProviderConfigure(t *testing.T) {
+	t.Parallel()
+
+	ansible := provider.Provider()
+
+	data := schema.TestResourceDataRaw(t, ansible.Schema, map[string]any{
+		"inventory_files":     []any{"hosts.ini"},
+		"extra_vars":          map[string]any{"env": "staging", "region": "eu-west-1"},
+		"extra_vars_json":     `{"env": "production", "ports": [80, 443], "tls": true}`,
+		"vault_password_file": "vault-password.txt",
+		"user":                "deploy",
+		"verbosity":           2,
+	})
+
+	meta, diags := ansible.ConfigureContextFunc(context.Background(), data)
+	require.False(t, diags.HasError(), diags)
+
+	config, ok := meta.(*providerutils.ProviderConfig)
+	require.True(t, ok)
+
+	assert.Equal(t, []string{"hosts.ini"}, config.InventoryFiles)
+	assert.Equal(t, "vault-password.txt", config.VaultPasswordFile)
+	assert.Equal(t, "deploy", config.User)
+	assert.Equal(t, 2, config.Verbosity)
+
+	// The JSON variables keep their types, and take precedence over the string ones.
+	assert.Equal(t, map[string]any{
+		"env":    "production",
+		"region": "eu-west-1",
+		"ports":  []any{json.Number("80"), json.Number("443")},
+		"tls":    true,
+	}, config.ExtraVars)
+}
+
+func TestProviderExtraVarsJSONInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{`["not", "an", "object"]`, `{"unterminated": `} {
+		diags := provider.Provider().Schema["extra_vars_json"].ValidateDiagFunc(value, nil)
+		assert.True(t, diags.HasError(), value)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceGroup() *schema.Resource {
@@ -37,6 +38,14 @@ func resourceGroup() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Description: "Map of variables.",
 			},
+			"variables_json": {
+				Type:             schema.TypeString,
+				Required:         false,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateJSONObject),
+				Description: "Variables as a JSON object, e.g. from jsonencode(), for values which are not strings " +
+					"(lists, objects, numbers, booleans). Merged on top of 'variables'.",
+			},
 		},
 	}
 }
@@ -2,9 +2,13 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceHost() *schema.Resource {
@@ -37,6 +41,14 @@ func resourceHost() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Description: "Map of variables.",
 			},
+			"variables_json": {
+				Type:             schema.TypeString,
+				Required:         false,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateJSONObject),
+				Description: "Variables as a JSON object, e.g. from jsonencode(), for values which are not strings " +
+					"(lists, objects, numbers, booleans). Merged on top of 'variables'.",
+			},
 		},
 	}
 }
@@ -73,3 +85,22 @@ func resourceHostDelete(_ context.Context, data *schema.ResourceData, _ any) dia
 
 	return nil
 }
+
+var errNotJSONObject = errors.New("expected a JSON object")
+
+// validateJSONObject checks that a string attribute holds a JSON object.
+func validateJSONObject(value any, key string) ([]string, []error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("%w in %q, got %T", errNotJSONObject, key, value)}
+	}
+
+	var object map[string]any
+
+	err := json.Unmarshal([]byte(str), &object)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%w in %q: %w", errNotJSONObject, key, err)}
+	}
+
+	return nil, nil
+}
@@ -1,16 +1,26 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/ansible/terraform-provider-ansible/providerutils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+const ansiblePlaybook = "ansible-playbook"
+
+// vaultPasswordAttributes are the sources of the vault password, exactly one must be set.
+var vaultPasswordAttributes = []string{"vault_password_file", "vault_password", "vault_password_command"}
+
 func resourceVault() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceVaultCreate,
@@ -18,6 +28,10 @@ func resourceVault() *schema.Resource {
 		UpdateContext: resourceVaultUpdate,
 		DeleteContext: resourceVaultDelete,
 
+		CustomizeDiff: customdiff.ComputedIf("args", func(_ context.Context, diff *schema.ResourceDiff, _ any) bool {
+			return diff.HasChanges(append([]string{"vault_file", "vault_id"}, vaultPasswordAttributes...)...)
+		}),
+
 		Schema: map[string]*schema.Schema{
 			"vault_file": {
 				Type:        schema.TypeString,
@@ -26,10 +40,31 @@ func resourceVault() *schema.Resource {
 				Description: "Path to encrypted vault file.",
 			},
 			"vault_password_file": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Optional:    false,
-				Description: "Path to vault password file.",
+				Type:         schema.TypeString,
+				Required:     false,
+				Optional:     true,
+				ExactlyOneOf: vaultPasswordAttributes,
+				Description:  "Path to vault password file.",
+			},
+
+			"vault_password": {
+				Type:         schema.TypeString,
+				Required:     false,
+				Optional:     true,
+				Sensitive:    true,
+				WriteOnly:    true,
+				ExactlyOneOf: vaultPasswordAttributes,
+				Description: "The vault password. It isn't stored in the state, so that the vault is only decrypted " +
+					"on apply, not on refresh.",
+			},
+
+			"vault_password_command": {
+				Type:         schema.TypeList,
+				Required:     false,
+				Optional:     true,
+				ExactlyOneOf: vaultPasswordAttributes,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Description:  "Command printing the vault password, and its arguments, e.g. `[\"pass\", \"ansible/vault\"]`.",
 			},
 
 			"vault_id": {
@@ -40,6 +75,26 @@ func resourceVault() *schema.Resource {
 				Description: "ID of the encrypted vault file.",
 			},
 
+			"use_ansible_vault_binary": {
+				Type:     schema.TypeBool,
+				Required: false,
+				Optional: true,
+				Default:  false,
+				Description: "Decrypt the vault file with the ansible-vault executable (binary), " +
+					"the provider's `ansible_vault_binary`, instead of the built-in decryption, " +
+					"which doesn't need Ansible to be installed.",
+			},
+
+			"format": {
+				Type:             schema.TypeString,
+				Required:         false,
+				Optional:         true,
+				Default:          providerutils.VaultFormatYAML,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(providerutils.VaultFormats, false)),
+				Description: "Format of the decrypted content, decoded into `data` and `json`: " +
+					"`yaml`, `json`, `env` (`NAME=value` lines) or `text`.",
+			},
+
 			// computed
 			"yaml": {
 				Type:      schema.TypeString,
@@ -47,6 +102,23 @@ func resourceVault() *schema.Resource {
 				Sensitive: true,
 			},
 
+			"data": {
+				Type:      schema.TypeMap,
+				Computed:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+				Description: "Top-level keys of the decrypted content: values which are not strings are JSON encoded. " +
+					"The keys of multi-document YAML content are merged, in order. Empty for `text` content.",
+			},
+
+			"json": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+				Description: "The decrypted content as JSON, with sorted keys. Multi-document YAML content " +
+					"is a list of the documents, and `text` content a string.",
+			},
+
 			// computed - for debug
 			"args": {
 				Type:     schema.TypeList,
@@ -69,14 +141,6 @@ func resourceVaultCreate(ctx context.Context, data *schema.ResourceData, meta an
 		})
 	}
 
-	vaultPasswordFile, okay := data.Get("vault_password_file").(string)
-	if !okay {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  "WARNING [ansible-vault]: couldn't get 'vault_password_file'!",
-		})
-	}
-
 	vaultID, okay := data.Get("vault_id").(string)
 	if !okay {
 		diags = append(diags, diag.Diagnostic{
@@ -87,36 +151,7 @@ func resourceVaultCreate(ctx context.Context, data *schema.ResourceData, meta an
 
 	data.SetId(vaultFile)
 
-	var args any
-
-	// Compute arguments (args)
-	if vaultID != "" {
-		args = []string{
-			"view",
-			"--vault-id",
-			vaultID + "@" + vaultPasswordFile,
-			vaultFile,
-		}
-	} else {
-		args = []string{
-			"view",
-			"--vault-password-file",
-			vaultPasswordFile,
-			vaultFile,
-		}
-	}
-
-	log.Print("LOG [ansible-vault]: ARGS")
-	log.Print(args)
-
-	err := data.Set("args", args)
-	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("ERROR [ansible-vault]: couldn't calculate 'args' variable! %s", err),
-			Detail:   ansiblePlaybook,
-		})
-	}
+	diags = append(diags, setVaultArgs(data, vaultFile, vaultID)...)
 
 	diagsFromRead := resourceVaultRead(ctx, data, meta)
 	diags = append(diags, diagsFromRead...)
@@ -124,9 +159,11 @@ func resourceVaultCreate(ctx context.Context, data *schema.ResourceData, meta an
 	return diags
 }
 
-func resourceVaultRead(ctx context.Context, data *schema.ResourceData, _ any) diag.Diagnostics {
+func resourceVaultRead(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
+	providerConfig, _ := meta.(*providerutils.ProviderConfig)
+
 	vaultFile, okay := data.Get("vault_file").(string)
 
 	if !okay {
@@ -136,44 +173,113 @@ func resourceVaultRead(ctx context.Context, data *schema.ResourceData, _ any) di
 		})
 	}
 
-	vaultPasswordFile, okay := data.Get("vault_password_file").(string)
+	argsTerraform, okay := data.Get("args").([]any)
 	if !okay {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Warning,
-			Summary:  "WARNING [ansible-vault]: couldn't get 'vault_password_file'!",
+			Summary:  "WARNING [ansible-vault]: couldn't get 'args'!",
 		})
 	}
 
-	argsTerraform, okay := data.Get("args").([]any)
-	if !okay {
+	password, diagsFromPassword := vaultPassword(data)
+	diags = append(diags, diagsFromPassword...)
+
+	// The write-only vault_password is only known on apply.
+	if !password.IsSet() {
+		log.Printf("LOG [ansible-vault]: vault_file = %s, not refreshed without vault_password\n", vaultFile)
+
+		return diags
+	}
+
+	log.Printf("LOG [ansible-vault]: vault_file = %s, vault_password_file = %s\n", vaultFile, password.Placeholder())
+
+	// The vault password is masked in the error output.
+	redactor := providerutils.NewRedactor()
+	redactor.AddSecretFile(password.File)
+	redactor.AddSecrets(password.Password)
+
+	vaultID, _ := data.Get("vault_id").(string)
+
+	var (
+		yamlString string
+		err        error
+	)
+
+	if useBinary, _ := data.Get("use_ansible_vault_binary").(bool); useBinary {
+		args, diagsFromUtils := providerutils.InterfaceToString(argsTerraform)
+		diags = append(diags, diagsFromUtils...)
+
+		yamlString, err = vaultViewBinary(ctx, providerConfig.VaultBinary(""), args, password, vaultID, redactor)
+	} else {
+		yamlString, err = vaultView(ctx, vaultFile, password, vaultID)
+	}
+
+	if err != nil {
 		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Warning,
-			Summary:  "WARNING [ansible-vault]: couldn't get 'args'!",
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("ERROR [ansible-vault]: couldn't decrypt '%s'!", vaultFile),
+			Detail:   redactor.Redact(err.Error()),
 		})
+
+		return diags
 	}
 
-	log.Printf("LOG [ansible-vault]: vault_file = %s, vault_password_file = %s\n", vaultFile, vaultPasswordFile)
+	err = data.Set("yaml", yamlString)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("ERROR [ansible-vault]: couldn't calculate 'yaml' variable! %s", err),
+			Detail:   ansiblePlaybook,
+		})
+	}
 
-	args, diagsFromUtils := providerutils.InterfaceToString(argsTerraform)
+	format, _ := data.Get("format").(string)
 
-	diags = append(diags, diagsFromUtils...)
+	decoded, jsonString, err := providerutils.DecodeVaultContent([]byte(yamlString), format)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("ERROR [ansible-vault]: couldn't decode '%s' as %s!", vaultFile, format),
+			Detail:   err.Error(),
+		})
 
-	cmd := exec.CommandContext(ctx, "ansible-vault", args...)
+		return diags
+	}
 
-	yamlString, err := cmd.CombinedOutput()
+	err = data.Set("data", decoded)
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  string(yamlString),
-			Detail:   ansiblePlaybook,
+			Summary:  fmt.Sprintf("ERROR [ansible-vault]: couldn't calculate 'data' variable! %s", err),
 		})
 	}
 
-	err = data.Set("yaml", string(yamlString))
+	err = data.Set("json", jsonString)
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  fmt.Sprintf("ERROR [ansible-vault]: couldn't calculate 'yaml' variable! %s", err),
+			Summary:  fmt.Sprintf("ERROR [ansible-vault]: couldn't calculate 'json' variable! %s", err),
+		})
+	}
+
+	return diags
+}
+
+// setVaultArgs computes the 'ansible-vault view' arguments (args) from the vault file, the vault ID
+// and the source of the vault password.
+func setVaultArgs(data *schema.ResourceData, vaultFile string, vaultID string) diag.Diagnostics {
+	password, diags := vaultPassword(data)
+
+	args := vaultArgs(vaultFile, password.Placeholder(), vaultID)
+
+	log.Print("LOG [ansible-vault]: ARGS")
+	log.Print(args)
+
+	err := data.Set("args", args)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("ERROR [ansible-vault]: couldn't calculate 'args' variable! %s", err),
 			Detail:   ansiblePlaybook,
 		})
 	}
@@ -181,8 +287,110 @@ func resourceVaultRead(ctx context.Context, data *schema.ResourceData, _ any) di
 	return diags
 }
 
+// vaultPassword reads the source of the vault password. The write-only vault_password is only
+// in the configuration, which is null when refreshing.
+func vaultPassword(data *schema.ResourceData) (providerutils.VaultPassword, diag.Diagnostics) {
+	var password providerutils.VaultPassword
+
+	password.File, _ = data.Get("vault_password_file").(string)
+
+	command, _ := data.Get("vault_password_command").([]any)
+
+	var diags diag.Diagnostics
+
+	password.Command, diags = providerutils.InterfaceToString(command)
+
+	if config := data.GetRawConfig(); !config.IsNull() {
+		if value := config.GetAttr("vault_password"); value.IsKnown() && !value.IsNull() {
+			password.Password = value.AsString()
+		}
+	}
+
+	return password, diags
+}
+
+// vaultArgs renders the 'ansible-vault view' arguments.
+func vaultArgs(vaultFile string, vaultPasswordFile string, vaultID string) []string {
+	if vaultID != "" {
+		return []string{
+			"view",
+			"--vault-id",
+			vaultID + "@" + vaultPasswordFile,
+			vaultFile,
+		}
+	}
+
+	return []string{
+		"view",
+		"--vault-password-file",
+		vaultPasswordFile,
+		vaultFile,
+	}
+}
+
+// vaultView decrypts a vault file with the built-in Ansible Vault implementation.
+func vaultView(ctx context.Context, vaultFile string, password providerutils.VaultPassword, vaultID string) (string, error) {
+	content, err := os.ReadFile(vaultFile)
+	if err != nil {
+		return "", fmt.Errorf("couldn't read the vault file: %w", err)
+	}
+
+	plaintext, err := providerutils.DecryptVaultWithPassword(ctx, content, password, vaultID)
+	if err != nil {
+		return "", fmt.Errorf("couldn't decrypt %s: %w", vaultFile, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// vaultViewBinary decrypts a vault file with 'ansible-vault view'. A vault password which isn't read from
+// a file is written to a temporary password file, removed afterwards.
+func vaultViewBinary(
+	ctx context.Context,
+	vaultBinary string,
+	args []string,
+	password providerutils.VaultPassword,
+	vaultID string,
+	redactor *providerutils.Redactor,
+) (string, error) {
+	passwordFile, cleanup, err := password.PasswordFile(ctx, vaultID)
+	if err != nil {
+		return "", fmt.Errorf("couldn't read the vault password: %w", err)
+	}
+
+	defer func() {
+		err := cleanup()
+		if err != nil {
+			log.Printf("LOG [ansible-vault]: %s", err)
+		}
+	}()
+
+	redactor.AddSecretFile(passwordFile)
+
+	args = password.ReplacePlaceholder(args, passwordFile)
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, vaultBinary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("%s %s failed: %w\n\n%s", vaultBinary, strings.Join(args, " "), err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
 func resourceVaultUpdate(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
-	return resourceVaultRead(ctx, data, meta)
+	vaultFile, _ := data.Get("vault_file").(string)
+	vaultID, _ := data.Get("vault_id").(string)
+
+	// The arguments change with the vault file, the vault ID and the source of the vault password.
+	diags := setVaultArgs(data, vaultFile, vaultID)
+
+	return append(diags, resourceVaultRead(ctx, data, meta)...)
 }
 
 func resourceVaultDelete(_ context.Context, data *schema.ResourceData, _ any) diag.Diagnostics {
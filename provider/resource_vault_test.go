@@ -0,0 +1,79 @@
+package provider_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/provider"
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeVault writes a vault file encrypted with the vault ID, and the password file.
+func writeVault(t *testing.T, vaultID string) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	vaultFile := filepath.Join(dir, "vault.yml")
+	passwordFile := filepath.Join(dir, "password.txt")
+
+	content, err := providerutils.EncryptVault([]byte("db_password: s3cr3t\n"), []byte("vault-password"), vaultID)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(vaultFile, content, 0o600))
+	require.NoError(t, os.WriteFile(passwordFile, []byte("vault-password\n"), 0o600))
+
+	return vaultFile, passwordFile
+}
+
+// The arguments are computed again when the vault ID or the vault password source changes.
+func TestVaultUpdateArgs(t *testing.T) {
+	t.Parallel()
+
+	vault := provider.Provider().ResourcesMap["ansible_vault"]
+	vaultFile, passwordFile := writeVault(t, "prod")
+
+	state := &terraform.InstanceState{
+		ID: vaultFile,
+		Attributes: map[string]string{
+			"id":                  vaultFile,
+			"vault_file":          vaultFile,
+			"vault_password_file": passwordFile,
+			"vault_id":            "",
+			"format":              providerutils.VaultFormatYAML,
+			"args.#":              "4",
+			"args.0":              "view",
+			"args.1":              "--vault-password-file",
+			"args.2":              passwordFile,
+			"args.3":              vaultFile,
+		},
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]any{
+		"vault_file":          vaultFile,
+		"vault_password_file": passwordFile,
+		"vault_id":            "prod",
+	})
+
+	diff, err := vault.Diff(context.Background(), state, config, &providerutils.ProviderConfig{})
+	require.NoError(t, err)
+	require.Contains(t, diff.Attributes, "args.#")
+	assert.True(t, diff.Attributes["args.#"].NewComputed)
+
+	data := schema.TestResourceDataRaw(t, vault.Schema, map[string]any{
+		"vault_file":          vaultFile,
+		"vault_password_file": passwordFile,
+		"vault_id":            "prod",
+	})
+	require.NoError(t, data.Set("args", []any{"view", "--vault-password-file", passwordFile, vaultFile}))
+
+	diags := vault.UpdateContext(context.Background(), data, &providerutils.ProviderConfig{})
+	require.False(t, diags.HasError(), diags)
+
+	assert.Equal(t, []any{"view", "--vault-id", "prod@" + passwordFile, vaultFile}, data.Get("args"))
+	assert.Equal(t, "db_password: s3cr3t\n", data.Get("yaml"))
+}
@@ -0,0 +1,90 @@
+# Shipped with terraform-provider-ansible and loaded automatically for every playbook run.
+# It never writes to stdout; results are dumped as JSON to the file named by TF_ANSIBLE_RESULTS_FILE.
+from __future__ import annotations
+
+import json
+import os
+
+from ansible.plugins.callback import CallbackBase
+
+DOCUMENTATION = """
+    name: terraform_results
+    type: aggregate
+    short_description: Collects structured playbook results for terraform-provider-ansible.
+    description:
+      - Writes the play recap, per-task results and custom stats (set with C(set_stats)) of a playbook run
+        to the file named by the C(TF_ANSIBLE_RESULTS_FILE) environment variable.
+"""
+
+
+class CallbackModule(CallbackBase):
+    CALLBACK_VERSION = 2.0
+    CALLBACK_TYPE = "aggregate"
+    CALLBACK_NAME = "terraform_results"
+    CALLBACK_NEEDS_ENABLED = False
+    CALLBACK_NEEDS_WHITELIST = False
+
+    def __init__(self):
+        super().__init__()
+        self._results_file = os.environ.get("TF_ANSIBLE_RESULTS_FILE")
+        self._play = ""
+        self._tasks = []
+
+    def v2_playbook_on_play_start(self, play):
+        self._play = play.get_name()
+
+    def _record(self, result, status):
+        message = result._result.get("msg", "")
+        if not message and status in ("failed", "unreachable"):
+            message = result._result.get("stderr", "")
+        if not isinstance(message, str):
+            message = json.dumps(message, default=str)
+
+        self._tasks.append(
+            {
+                "play": self._play,
+                "task": result._task.get_name(),
+                "host": result._host.get_name(),
+                "status": status,
+                "message": message,
+            }
+        )
+
+    def v2_runner_on_ok(self, result):
+        self._record(result, "changed" if result._result.get("changed", False) else "ok")
+
+    def v2_runner_on_failed(self, result, ignore_errors=False):
+        self._record(result, "ignored" if ignore_errors else "failed")
+
+    def v2_runner_on_skipped(self, result):
+        self._record(result, "skipped")
+
+    def v2_runner_on_unreachable(self, result):
+        self._record(result, "unreachable")
+
+    def v2_playbook_on_stats(self, stats):
+        if not self._results_file:
+            return
+
+        recap = []
+        for host in sorted(stats.processed.keys()):
+            summary = stats.summarize(host)
+            recap.append(
+                {
+                    "host": host,
+                    "ok": summary["ok"],
+                    "changed": summary["changed"],
+                    "unreachable": summary["unreachable"],
+                    "failed": summary["failures"],
+                    "skipped": summary["skipped"],
+                    "rescued": summary["rescued"],
+                    "ignored": summary["ignored"],
+                }
+            )
+
+        with open(self._results_file, "w", encoding="utf-8") as results_file:
+            json.dump(
+                {"recap": recap, "tasks": self._tasks, "custom_stats": stats.custom},
+                results_file,
+                default=str,
+            )
@@ -0,0 +1,142 @@
+package providerutils
+
+import (
+	"maps"
+)
+
+const (
+	DefaultAnsiblePlaybookBinary  = "ansible-playbook"
+	DefaultAnsibleVaultBinary     = "ansible-vault"
+	DefaultAnsibleInventoryBinary = "ansible-inventory"
+)
+
+// ProviderConfig holds the parsed `provider "ansible" {}` block.
+// It is built by the SDKv2 provider and shared with the framework provider through Meta(),
+// so both ansible_playbook and ansible_playbook_run fall back to the same defaults.
+// All methods are safe to call on a nil *ProviderConfig (unconfigured provider).
+type ProviderConfig struct {
+	AnsiblePlaybookBinary  string
+	AnsibleVaultBinary     string
+	AnsibleInventoryBinary string
+	InventoryFiles         []string
+	ExtraVars              map[string]any
+	VaultPasswordFile      string
+	VaultID                string
+	User                   string
+	PrivateKeyFile         string
+	Verbosity              int
+}
+
+// orProviderDefault returns the given value, or the provider default if it isn't set (nil).
+// Zero values are kept, so that a resource can override a provider default with e.g. a verbosity of 0.
+func orProviderDefault[T any](value *T, fallback T) T {
+	if value != nil {
+		return *value
+	}
+
+	return fallback
+}
+
+func orDefault[T comparable](value, fallback T) T {
+	var zero T
+	if value != zero {
+		return value
+	}
+
+	return fallback
+}
+
+// PlaybookBinary returns the ansible-playbook executable to use,
+// preferring the given per-resource value over the provider default.
+func (c *ProviderConfig) PlaybookBinary(value string) string {
+	if c == nil {
+		return orDefault(value, DefaultAnsiblePlaybookBinary)
+	}
+
+	return orDefault(value, orDefault(c.AnsiblePlaybookBinary, DefaultAnsiblePlaybookBinary))
+}
+
+// VaultBinary returns the ansible-vault executable to use.
+func (c *ProviderConfig) VaultBinary(value string) string {
+	if c == nil {
+		return orDefault(value, DefaultAnsibleVaultBinary)
+	}
+
+	return orDefault(value, orDefault(c.AnsibleVaultBinary, DefaultAnsibleVaultBinary))
+}
+
+// InventoryBinary returns the ansible-inventory executable to use.
+func (c *ProviderConfig) InventoryBinary(value string) string {
+	if c == nil {
+		return orDefault(value, DefaultAnsibleInventoryBinary)
+	}
+
+	return orDefault(value, orDefault(c.AnsibleInventoryBinary, DefaultAnsibleInventoryBinary))
+}
+
+// PlaybookInventoryFiles returns the given inventory files, or the provider defaults if they are nil (unset).
+// An empty list is kept, so that a resource can opt out of the provider inventory files.
+func (c *ProviderConfig) PlaybookInventoryFiles(value *[]string) []string {
+	if c == nil {
+		return orProviderDefault(value, nil)
+	}
+
+	return orProviderDefault(value, c.InventoryFiles)
+}
+
+// PlaybookExtraVars merges the given extra vars on top of the provider defaults.
+func (c *ProviderConfig) PlaybookExtraVars(value map[string]any) map[string]any {
+	merged := map[string]any{}
+	if c != nil {
+		maps.Copy(merged, c.ExtraVars)
+	}
+
+	maps.Copy(merged, value)
+
+	return merged
+}
+
+// PlaybookVaultPasswordFile returns the given vault password file, or the provider default if it is nil (unset).
+func (c *ProviderConfig) PlaybookVaultPasswordFile(value *string) string {
+	if c == nil {
+		return orProviderDefault(value, "")
+	}
+
+	return orProviderDefault(value, c.VaultPasswordFile)
+}
+
+// PlaybookVaultID returns the given vault ID, or the provider default if it is nil (unset).
+func (c *ProviderConfig) PlaybookVaultID(value *string) string {
+	if c == nil {
+		return orProviderDefault(value, "")
+	}
+
+	return orProviderDefault(value, c.VaultID)
+}
+
+// PlaybookUser returns the given connection user, or the provider default if it is nil (unset).
+func (c *ProviderConfig) PlaybookUser(value *string) string {
+	if c == nil {
+		return orProviderDefault(value, "")
+	}
+
+	return orProviderDefault(value, c.User)
+}
+
+// PlaybookPrivateKeyFile returns the given private key file, or the provider default if it is nil (unset).
+func (c *ProviderConfig) PlaybookPrivateKeyFile(value *string) string {
+	if c == nil {
+		return orProviderDefault(value, "")
+	}
+
+	return orProviderDefault(value, c.PrivateKeyFile)
+}
+
+// PlaybookVerbosity returns the given verbosity, or the provider default if it is nil (unset).
+func (c *ProviderConfig) PlaybookVerbosity(value *int) int {
+	if c == nil {
+		return orProviderDefault(value, 0)
+	}
+
+	return orProviderDefault(value, c.Verbosity)
+}
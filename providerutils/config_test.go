@@ -0,0 +1,89 @@
+package providerutils_test
+
+import (
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func pointer[T any](value T) *T {
+	return &value
+}
+
+func TestProviderConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	config := &providerutils.ProviderConfig{
+		AnsiblePlaybookBinary: "/opt/ansible/bin/ansible-playbook",
+		InventoryFiles:        []string{"hosts.ini"},
+		VaultPasswordFile:     "vault-password.txt",
+		VaultID:               "prod",
+		User:                  "deploy",
+		PrivateKeyFile:        "id_ed25519",
+		Verbosity:             2,
+	}
+
+	// Unset values fall back to the provider defaults.
+	assert.Equal(t, "/opt/ansible/bin/ansible-playbook", config.PlaybookBinary(""))
+	assert.Equal(t, providerutils.DefaultAnsibleVaultBinary, config.VaultBinary(""))
+	assert.Equal(t, []string{"hosts.ini"}, config.PlaybookInventoryFiles(nil))
+	assert.Equal(t, "vault-password.txt", config.PlaybookVaultPasswordFile(nil))
+	assert.Equal(t, "prod", config.PlaybookVaultID(nil))
+	assert.Equal(t, "deploy", config.PlaybookUser(nil))
+	assert.Equal(t, "id_ed25519", config.PlaybookPrivateKeyFile(nil))
+	assert.Equal(t, 2, config.PlaybookVerbosity(nil))
+
+	// Set values override them, zero values included.
+	assert.Equal(t, "ansible-playbook", config.PlaybookBinary("ansible-playbook"))
+	assert.Equal(t, []string{"other.ini"}, config.PlaybookInventoryFiles(&[]string{"other.ini"}))
+	assert.Empty(t, config.PlaybookInventoryFiles(&[]string{}))
+	assert.Empty(t, config.PlaybookVaultPasswordFile(pointer("")))
+	assert.Empty(t, config.PlaybookVaultID(pointer("")))
+	assert.Equal(t, "admin", config.PlaybookUser(pointer("admin")))
+	assert.Empty(t, config.PlaybookUser(pointer("")))
+	assert.Empty(t, config.PlaybookPrivateKeyFile(pointer("")))
+	assert.Equal(t, 0, config.PlaybookVerbosity(pointer(0)))
+}
+
+// An unconfigured provider has no defaults.
+func TestProviderConfigNil(t *testing.T) {
+	t.Parallel()
+
+	var config *providerutils.ProviderConfig
+
+	assert.Equal(t, providerutils.DefaultAnsiblePlaybookBinary, config.PlaybookBinary(""))
+	assert.Equal(t, providerutils.DefaultAnsibleInventoryBinary, config.InventoryBinary(""))
+	assert.Nil(t, config.PlaybookInventoryFiles(nil))
+	assert.Equal(t, []string{"hosts.ini"}, config.PlaybookInventoryFiles(&[]string{"hosts.ini"}))
+	assert.Empty(t, config.PlaybookVaultPasswordFile(nil))
+	assert.Equal(t, "prod", config.PlaybookVaultID(pointer("prod")))
+	assert.Empty(t, config.PlaybookUser(nil))
+	assert.Empty(t, config.PlaybookPrivateKeyFile(nil))
+	assert.Equal(t, 3, config.PlaybookVerbosity(pointer(3)))
+	assert.Equal(t, map[string]any{"port": 22}, config.PlaybookExtraVars(map[string]any{"port": 22}))
+}
+
+func TestProviderConfigExtraVars(t *testing.T) {
+	t.Parallel()
+
+	config := &providerutils.ProviderConfig{
+		ExtraVars: map[string]any{
+			"env":      "staging",
+			"ports":    []any{80, 443},
+			"features": map[string]any{"tls": true},
+		},
+	}
+
+	merged := config.PlaybookExtraVars(map[string]any{"env": "production", "replicas": 3})
+
+	assert.Equal(t, map[string]any{
+		"env":      "production",
+		"ports":    []any{80, 443},
+		"features": map[string]any{"tls": true},
+		"replicas": 3,
+	}, merged)
+
+	// The provider defaults aren't modified.
+	assert.Equal(t, "staging", config.ExtraVars["env"])
+}
@@ -0,0 +1,380 @@
+package providerutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ArgsContentHashKey is the content hash key of the rendered ansible-playbook arguments.
+const ArgsContentHashKey = "args"
+
+// Keywords pointing to files or roles, with or without the "ansible.builtin." prefix.
+var (
+	playbookIncludeKeywords = []string{"import_playbook"}
+	taskIncludeKeywords     = []string{"include_tasks", "import_tasks"}
+	roleIncludeKeywords     = []string{"include_role", "import_role"}
+	varsIncludeKeywords     = []string{"include_vars"}
+	taskListKeywords        = []string{"tasks", "pre_tasks", "post_tasks", "handlers", "block", "rescue", "always"}
+)
+
+// ContentHashes computes a SHA-256 digest of everything a playbook run depends on:
+// the playbook itself, the playbooks, task files, roles and variable files it references,
+// the given extra files (var files, vault files), the rendered arguments and the extra vars.
+// The result maps each path (or ArgsContentHashKey) to its digest, so that a plan shows what changed.
+//
+// References which can't be resolved statically (templated paths, roles from collections) are ignored.
+func ContentHashes(playbook string, files []string, args []string, extraVars map[string]any) (map[string]string, error) {
+	walker := contentWalker{
+		hashes:  map[string]string{},
+		visited: map[string]bool{},
+	}
+
+	err := walker.hashPlaybook(playbook, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		err := walker.hashPath(file, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	argsHash, err := ArgsContentHash(args, extraVars)
+	if err != nil {
+		return nil, err
+	}
+
+	walker.hashes[ArgsContentHashKey] = argsHash
+
+	return walker.hashes, nil
+}
+
+// ArgsContentHash is the digest of the rendered arguments and of the extra vars passed in a file,
+// stored under ArgsContentHashKey.
+func ArgsContentHash(args []string, extraVars map[string]any) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(strings.Join(args, "\x00")))
+
+	if len(extraVars) > 0 {
+		payload, err := json.Marshal(extraVars)
+		if err != nil {
+			return "", fmt.Errorf("couldn't encode extra vars: %w", err)
+		}
+
+		hash.Write([]byte{0})
+		hash.Write(payload)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+type contentWalker struct {
+	hashes  map[string]string
+	visited map[string]bool
+}
+
+// hashPath hashes a file, or all the files of a directory.
+// Missing paths are an error only if required.
+func (w *contentWalker) hashPath(path string, required bool) error {
+	if w.visited[path] {
+		return nil
+	}
+
+	w.visited[path] = true
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if !required && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("couldn't read %s: %w", path, err)
+	}
+
+	hash := sha256.New()
+
+	if !info.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("couldn't read %s: %w", path, err)
+		}
+
+		hash.Write(content)
+		w.hashes[path] = hex.EncodeToString(hash.Sum(nil))
+
+		return nil
+	}
+
+	// filepath.WalkDir visits files in lexical order, so the digest is stable.
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+
+		fileHash := sha256.Sum256(content)
+		hash.Write([]byte(filepath.ToSlash(relative)))
+		hash.Write(fileHash[:])
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't read %s: %w", path, err)
+	}
+
+	w.hashes[path] = hex.EncodeToString(hash.Sum(nil))
+
+	return nil
+}
+
+func (w *contentWalker) hashPlaybook(playbook string, required bool) error {
+	if w.visited[playbook] {
+		return nil
+	}
+
+	err := w.hashPath(playbook, required)
+	if err != nil {
+		return err
+	}
+
+	// Optional playbook which doesn't exist
+	if _, ok := w.hashes[playbook]; !ok {
+		return nil
+	}
+
+	var plays []map[string]any
+
+	content, err := os.ReadFile(playbook)
+	if err != nil {
+		return fmt.Errorf("couldn't read %s: %w", playbook, err)
+	}
+
+	// Playbooks which can't be parsed are still hashed, ansible-playbook will report the error.
+	if yaml.Unmarshal(content, &plays) != nil {
+		return nil
+	}
+
+	dir := filepath.Dir(playbook)
+
+	for _, play := range plays {
+		for _, imported := range fileReferences(play, playbookIncludeKeywords) {
+			err := w.hashPlaybook(resolveReference(dir, imported), false)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, varsFile := range stringList(play["vars_files"]) {
+			err := w.hashPath(resolveReference(dir, varsFile), false)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, role := range roleReferences(play["roles"]) {
+			err := w.hashPath(resolveReference(dir, role), false)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := w.hashTasks(play, dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hashTasks hashes the files referenced by the tasks of a play, block or task file.
+// Relative paths are resolved from dir, the directory of the playbook or of the including task file.
+func (w *contentWalker) hashTasks(parent map[string]any, dir string) error {
+	for _, keyword := range taskListKeywords {
+		tasks, ok := parent[keyword].([]any)
+		if !ok {
+			continue
+		}
+
+		for _, task := range tasks {
+			taskMap, ok := task.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			err := w.hashTask(taskMap, dir)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *contentWalker) hashTask(task map[string]any, dir string) error {
+	for _, taskFile := range fileReferences(task, taskIncludeKeywords) {
+		path := resolveReference(dir, taskFile)
+		if w.visited[path] {
+			continue
+		}
+
+		err := w.hashPath(path, false)
+		if err != nil {
+			return err
+		}
+
+		var tasks []any
+
+		content, err := os.ReadFile(path)
+		if err != nil || yaml.Unmarshal(content, &tasks) != nil {
+			continue
+		}
+
+		err = w.hashTasks(map[string]any{"tasks": tasks}, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, varsFile := range fileReferences(task, varsIncludeKeywords) {
+		err := w.hashPath(resolveReference(dir, varsFile), false)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, keyword := range roleIncludeKeywords {
+		for _, name := range []string{keyword, "ansible.builtin." + keyword} {
+			role, ok := task[name].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			for _, rolePath := range roleReferences([]any{role}) {
+				err := w.hashPath(resolveReference(dir, rolePath), false)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return w.hashTasks(task, dir)
+}
+
+// fileReferences returns the static file paths given to any of the keywords,
+// either as a string or in the `file` option.
+func fileReferences(task map[string]any, keywords []string) []string {
+	references := []string{}
+
+	for _, keyword := range keywords {
+		for _, name := range []string{keyword, "ansible.builtin." + keyword} {
+			var reference string
+
+			switch value := task[name].(type) {
+			case string:
+				reference = value
+			case map[string]any:
+				reference, _ = value["file"].(string)
+			}
+
+			if isStaticReference(reference) {
+				references = append(references, reference)
+			}
+		}
+	}
+
+	return references
+}
+
+// roleReferences returns the paths of the roles, given as strings or as maps with `role` or `name`,
+// relative to the playbook directory. Roles from collections (namespace.collection.role) are skipped.
+func roleReferences(roles any) []string {
+	references := []string{}
+
+	roleList, ok := roles.([]any)
+	if !ok {
+		return references
+	}
+
+	for _, role := range roleList {
+		var name string
+
+		switch value := role.(type) {
+		case string:
+			name = value
+		case map[string]any:
+			name, ok = value["role"].(string)
+			if !ok {
+				name, _ = value["name"].(string)
+			}
+		}
+
+		switch {
+		case !isStaticReference(name):
+		case strings.ContainsRune(name, '/'):
+			references = append(references, name)
+		case !strings.Contains(name, "."):
+			references = append(references, filepath.Join("roles", name))
+		}
+	}
+
+	return references
+}
+
+func stringList(value any) []string {
+	result := []string{}
+
+	list, ok := value.([]any)
+	if !ok {
+		return result
+	}
+
+	for _, item := range list {
+		str, ok := item.(string)
+		if ok && isStaticReference(str) {
+			result = append(result, str)
+		}
+	}
+
+	return result
+}
+
+// resolveReference resolves a path relative to dir, the directory of the referencing file.
+// Absolute paths are kept as they are.
+func resolveReference(dir string, reference string) string {
+	if filepath.IsAbs(reference) {
+		return reference
+	}
+
+	return filepath.Join(dir, reference)
+}
+
+func isStaticReference(reference string) bool {
+	return reference != "" && !strings.Contains(reference, "{{")
+}
@@ -0,0 +1,200 @@
+package providerutils_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles writes the files, by path relative to dir, creating their directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func hashedPaths(hashes map[string]string) []string {
+	paths := []string{}
+
+	for path := range hashes {
+		paths = append(paths, path)
+	}
+
+	slices.Sort(paths)
+
+	return paths
+}
+
+func TestContentHashes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"site.yml": `
+- import_playbook: common.yml
+- hosts: all
+  vars_files:
+    - vars/main.yml
+    - "vars/{{ env }}.yml"
+  roles:
+    - web
+    - role: db
+    - community.general.some_role
+  tasks:
+    - ansible.builtin.include_tasks: tasks/setup.yml
+    - include_vars:
+        file: vars/extra.yml
+    - import_role:
+        name: monitoring
+    - block:
+        - import_tasks: tasks/missing.yml
+`,
+		"common.yml": `
+- hosts: all
+  tasks:
+    - import_tasks: tasks/common.yml
+`,
+		"vars/main.yml":               "port: 80\n",
+		"vars/extra.yml":              "debug: true\n",
+		"tasks/setup.yml":             "- include_tasks: nested.yml\n",
+		"tasks/nested.yml":            "- debug: msg=nested\n",
+		"tasks/common.yml":            "- debug: msg=common\n",
+		"roles/web/tasks/main.yml":    "- debug: msg=web\n",
+		"roles/db/tasks/main.yml":     "- debug: msg=db\n",
+		"roles/monitoring/vars/x.yml": "x: 1\n",
+		"group_vars/all.yml":          "region: eu\n",
+	})
+
+	playbook := filepath.Join(dir, "site.yml")
+	extraFile := filepath.Join(dir, "group_vars", "all.yml")
+
+	hashes, err := providerutils.ContentHashes(playbook, []string{extraFile}, []string{"site.yml"}, nil)
+	require.NoError(t, err)
+
+	// Templated paths, roles from collections and missing optional references are skipped,
+	// task files are resolved from the directory of the including task file.
+	assert.Equal(t, []string{
+		filepath.Join(dir, "common.yml"),
+		filepath.Join(dir, "group_vars", "all.yml"),
+		filepath.Join(dir, "roles", "db"),
+		filepath.Join(dir, "roles", "monitoring"),
+		filepath.Join(dir, "roles", "web"),
+		filepath.Join(dir, "site.yml"),
+		filepath.Join(dir, "tasks", "common.yml"),
+		filepath.Join(dir, "tasks", "nested.yml"),
+		filepath.Join(dir, "tasks", "setup.yml"),
+		filepath.Join(dir, "vars", "extra.yml"),
+		filepath.Join(dir, "vars", "main.yml"),
+		providerutils.ArgsContentHashKey,
+	}, hashedPaths(hashes))
+
+	// A change of a role file changes the digest of the role only.
+	writeFiles(t, dir, map[string]string{"roles/web/tasks/main.yml": "- debug: msg=changed\n"})
+
+	changed, err := providerutils.ContentHashes(playbook, []string{extraFile}, []string{"site.yml"}, nil)
+	require.NoError(t, err)
+
+	for path, hash := range hashes {
+		if path == filepath.Join(dir, "roles", "web") {
+			assert.NotEqual(t, hash, changed[path], path)
+		} else {
+			assert.Equal(t, hash, changed[path], path)
+		}
+	}
+}
+
+// References given as absolute paths aren't resolved from the playbook directory.
+func TestContentHashesAbsoluteReferences(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	shared := t.TempDir()
+
+	writeFiles(t, shared, map[string]string{
+		"common.yml":                   "- hosts: all\n  tasks: []\n",
+		"vars.yml":                     "port: 80\n",
+		"tasks.yml":                    "- include_tasks: nested.yml\n",
+		"nested.yml":                   "- debug: msg=nested\n",
+		"roles/nginx/tasks/main.yml":   "- debug: msg=nginx\n",
+		"roles/certbot/tasks/main.yml": "- debug: msg=certbot\n",
+	})
+
+	writeFiles(t, dir, map[string]string{
+		"site.yml": `
+- import_playbook: ` + filepath.Join(shared, "common.yml") + `
+- hosts: all
+  vars_files:
+    - ` + filepath.Join(shared, "vars.yml") + `
+  roles:
+    - ` + filepath.Join(shared, "roles", "nginx") + `
+  tasks:
+    - include_tasks: ` + filepath.Join(shared, "tasks.yml") + `
+    - include_role:
+        name: ` + filepath.Join(shared, "roles", "certbot") + `
+`,
+	})
+
+	hashes, err := providerutils.ContentHashes(filepath.Join(dir, "site.yml"), nil, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "site.yml"),
+		filepath.Join(shared, "common.yml"),
+		filepath.Join(shared, "nested.yml"),
+		filepath.Join(shared, "roles", "certbot"),
+		filepath.Join(shared, "roles", "nginx"),
+		filepath.Join(shared, "tasks.yml"),
+		filepath.Join(shared, "vars.yml"),
+		providerutils.ArgsContentHashKey,
+	}, hashedPaths(hashes))
+}
+
+// The playbook and the extra files are required, the files they reference are optional.
+func TestContentHashesMissingFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{"site.yml": "- import_playbook: missing.yml\n"})
+
+	_, err := providerutils.ContentHashes(filepath.Join(dir, "missing.yml"), nil, nil, nil)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = providerutils.ContentHashes(filepath.Join(dir, "site.yml"), []string{filepath.Join(dir, "vars.yml")}, nil, nil)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	hashes, err := providerutils.ContentHashes(filepath.Join(dir, "site.yml"), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "site.yml"), providerutils.ArgsContentHashKey}, hashedPaths(hashes))
+}
+
+func TestArgsContentHash(t *testing.T) {
+	t.Parallel()
+
+	hash, err := providerutils.ArgsContentHash([]string{"-v", "site.yml"}, nil)
+	require.NoError(t, err)
+
+	same, err := providerutils.ArgsContentHash([]string{"-v", "site.yml"}, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	// The arguments are separated, so that they can't be joined differently.
+	joined, err := providerutils.ArgsContentHash([]string{"-vsite.yml"}, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, joined)
+
+	withVars, err := providerutils.ArgsContentHash([]string{"-v", "site.yml"}, map[string]any{"port": 80})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, withVars)
+}
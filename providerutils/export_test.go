@@ -0,0 +1,19 @@
+package providerutils
+
+// Unexported functions and errors tested by the providerutils_test package.
+var (
+	PythonLiteral     = pythonLiteral
+	ExpandHostPattern = expandHostPattern
+	ParseVault        = parseVault
+
+	ErrCyclicGroups       = errCyclicGroups
+	ErrInvalidHostPattern = errInvalidHostPattern
+	ErrInvalidINILine     = errInvalidINILine
+	ErrInvalidSectionType = errInvalidSectionType
+
+	ErrNotVault         = errNotVault
+	ErrUnsupportedVault = errUnsupportedVault
+	ErrInvalidVault     = errInvalidVault
+	ErrVaultDecryption  = errVaultDecryption
+	ErrEmptyPassword    = errEmptyPassword
+)
@@ -0,0 +1,123 @@
+package providerutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+)
+
+// InventoryHost is a host of a generated inventory, with its groups and host variables.
+type InventoryHost struct {
+	Name   string
+	Groups []string
+	Vars   map[string]any
+}
+
+// InventoryGroup is a group of a generated inventory, with its child groups and group variables.
+type InventoryGroup struct {
+	Name     string
+	Children []string
+	Vars     map[string]any
+}
+
+type inventoryGroup struct {
+	Hosts    map[string]map[string]any `json:"hosts,omitempty"`
+	Vars     map[string]any            `json:"vars,omitempty"`
+	Children map[string]struct{}       `json:"children,omitempty"`
+}
+
+// BuildInventory writes the hosts and groups into a temporary inventory file, in the JSON inventory format
+// (the format of the ansible_inventory data source), and returns its path.
+// Hosts without groups are added to DefaultHostGroup. The variables of a host or a group listed several times
+// are merged.
+func BuildInventory(inventoryDest string, hosts []InventoryHost, groups []InventoryGroup) (string, error) {
+	hostVars := map[string]map[string]any{}
+	inventory := map[string]*inventoryGroup{}
+
+	getGroup := func(name string) *inventoryGroup {
+		group, ok := inventory[name]
+		if !ok {
+			group = &inventoryGroup{}
+			inventory[name] = group
+		}
+
+		return group
+	}
+
+	for _, host := range hosts {
+		vars, ok := hostVars[host.Name]
+		if !ok {
+			vars = map[string]any{}
+			hostVars[host.Name] = vars
+		}
+
+		maps.Copy(vars, host.Vars)
+
+		hostGroups := host.Groups
+		if len(hostGroups) == 0 {
+			hostGroups = []string{DefaultHostGroup}
+		}
+
+		for _, name := range hostGroups {
+			group := getGroup(name)
+			if group.Hosts == nil {
+				group.Hosts = map[string]map[string]any{}
+			}
+
+			group.Hosts[host.Name] = vars
+		}
+	}
+
+	for _, group := range groups {
+		inventoryGroup := getGroup(group.Name)
+
+		if len(group.Vars) > 0 {
+			if inventoryGroup.Vars == nil {
+				inventoryGroup.Vars = map[string]any{}
+			}
+
+			maps.Copy(inventoryGroup.Vars, group.Vars)
+		}
+
+		for _, child := range group.Children {
+			if inventoryGroup.Children == nil {
+				inventoryGroup.Children = map[string]struct{}{}
+			}
+
+			inventoryGroup.Children[child] = struct{}{}
+		}
+	}
+
+	content, err := json.Marshal(inventory)
+	if err != nil {
+		return "", fmt.Errorf("couldn't encode inventory: %w", err)
+	}
+
+	return WriteTempFile(inventoryDest, content)
+}
+
+// WriteTempFile writes the content into a new private temporary file, and returns its path.
+func WriteTempFile(pattern string, content []byte) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("couldn't create temporary file: %w", err)
+	}
+
+	_, err = file.Write(content)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+
+		return "", fmt.Errorf("couldn't write temporary file: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		_ = os.Remove(file.Name())
+
+		return "", fmt.Errorf("couldn't write temporary file: %w", err)
+	}
+
+	return file.Name(), nil
+}
@@ -0,0 +1,510 @@
+package providerutils
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Inventory formats read by ParseInventory.
+const (
+	InventoryFormatINI  = "ini"
+	InventoryFormatYAML = "yaml"
+	InventoryFormatJSON = "json"
+)
+
+// Groups which Ansible adds to every inventory: 'all' holds every host and group,
+// 'ungrouped' the hosts without any other group.
+const (
+	allGroup       = "all"
+	ungroupedGroup = "ungrouped"
+)
+
+var (
+	errUnknownInventoryFormat = errors.New("unknown inventory format")
+	errInvalidHostPattern     = errors.New("invalid host pattern")
+	errCyclicGroups           = errors.New("group is its own descendant")
+)
+
+// Inventory is an Ansible inventory read from a file. InventoryHost.Groups holds the groups
+// the hosts are directly declared in.
+type Inventory struct {
+	Hosts  map[string]*InventoryHost
+	Groups map[string]*InventoryGroup
+}
+
+// InventoryFileFormat guesses the format of an inventory file from its extension, like Ansible does:
+// '.yml', '.yaml' and '.json' files are YAML or JSON inventories, other files are INI inventories.
+func InventoryFileFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		return InventoryFormatYAML
+	case ".json":
+		return InventoryFormatJSON
+	default:
+		return InventoryFormatINI
+	}
+}
+
+// ParseInventory reads an INI, YAML or JSON inventory. JSON inventories can have the layout of YAML inventories,
+// or the one of 'ansible-inventory --list', with lists of hosts and children and '_meta.hostvars'.
+func ParseInventory(content []byte, format string) (*Inventory, error) {
+	inventory := &Inventory{
+		Hosts:  map[string]*InventoryHost{},
+		Groups: map[string]*InventoryGroup{},
+	}
+
+	var err error
+
+	switch format {
+	case InventoryFormatINI:
+		err = inventory.parseINI(string(content))
+	case InventoryFormatYAML, InventoryFormatJSON:
+		// JSON is YAML.
+		err = inventory.parseYAML(content)
+	default:
+		err = fmt.Errorf("%w %q, expected one of %q, %q or %q",
+			errUnknownInventoryFormat, format, InventoryFormatINI, InventoryFormatYAML, InventoryFormatJSON)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = inventory.depths()
+	if err != nil {
+		return nil, err
+	}
+
+	return inventory, nil
+}
+
+func (i *Inventory) group(name string) *InventoryGroup {
+	group, ok := i.Groups[name]
+	if !ok {
+		group = &InventoryGroup{Name: name, Children: []string{}, Vars: map[string]any{}}
+		i.Groups[name] = group
+	}
+
+	return group
+}
+
+func (i *Inventory) addChild(parent string, child string) {
+	group := i.group(parent)
+	if !slices.Contains(group.Children, child) {
+		group.Children = append(group.Children, child)
+	}
+
+	i.group(child)
+}
+
+// addHost declares a host in a group ("" for none), merging its variables.
+func (i *Inventory) addHost(name string, group string, vars map[string]any) {
+	host, ok := i.Hosts[name]
+	if !ok {
+		host = &InventoryHost{Name: name, Groups: []string{}, Vars: map[string]any{}}
+		i.Hosts[name] = host
+	}
+
+	if group != "" && !slices.Contains(host.Groups, group) {
+		host.Groups = append(host.Groups, group)
+		i.group(group)
+	}
+
+	maps.Copy(host.Vars, vars)
+}
+
+// GroupHosts returns the hosts declared directly in a group, sorted.
+func (i *Inventory) GroupHosts(group string) []string {
+	hosts := []string{}
+
+	for name, host := range i.Hosts {
+		if slices.Contains(host.Groups, group) {
+			hosts = append(hosts, name)
+		}
+	}
+
+	slices.Sort(hosts)
+
+	return hosts
+}
+
+// HostGroups returns all the groups of a host, including the parents of the groups it is declared in,
+// but not 'all' and 'ungrouped', sorted. This is the 'group_names' variable of Ansible.
+func (i *Inventory) HostGroups(name string) []string {
+	groups := map[string]bool{}
+
+	var visit func(group string)
+	visit = func(group string) {
+		if groups[group] {
+			return
+		}
+
+		groups[group] = true
+
+		for parent, parentGroup := range i.Groups {
+			if slices.Contains(parentGroup.Children, group) {
+				visit(parent)
+			}
+		}
+	}
+
+	if host, ok := i.Hosts[name]; ok {
+		for _, group := range host.Groups {
+			visit(group)
+		}
+	}
+
+	delete(groups, allGroup)
+	delete(groups, ungroupedGroup)
+
+	names := slices.AppendSeq([]string{}, maps.Keys(groups))
+	slices.Sort(names)
+
+	return names
+}
+
+// HostVars returns the variables of a host merged like Ansible does: the variables of 'all' first,
+// then the ones of its groups, parents before children (then by name), then the host variables.
+func (i *Inventory) HostVars(name string) map[string]any {
+	vars := map[string]any{}
+
+	host, ok := i.Hosts[name]
+	if !ok {
+		return vars
+	}
+
+	depths, err := i.depths()
+	if err != nil {
+		return vars
+	}
+
+	groups := append([]string{allGroup}, i.HostGroups(name)...)
+	if len(groups) == 1 || slices.Contains(host.Groups, ungroupedGroup) {
+		groups = append(groups, ungroupedGroup)
+	}
+
+	slices.SortStableFunc(groups, func(a, b string) int {
+		if depths[a] != depths[b] {
+			return depths[a] - depths[b]
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	for _, group := range groups {
+		if group, ok := i.Groups[group]; ok {
+			maps.Copy(vars, group.Vars)
+		}
+	}
+
+	maps.Copy(vars, host.Vars)
+
+	return vars
+}
+
+// depths returns the depth of each group in the hierarchy: 0 for 'all', 1 for the top level groups,
+// and one more than the deepest parent for the child groups. Cycles are an error.
+func (i *Inventory) depths() (map[string]int, error) {
+	depths := map[string]int{allGroup: 0}
+	visiting := map[string]bool{}
+
+	var visit func(group string, depth int, path []string) error
+	visit = func(group string, depth int, path []string) error {
+		if visiting[group] {
+			return fmt.Errorf("%w: %s", errCyclicGroups, strings.Join(append(path, group), " > "))
+		}
+
+		if current, ok := depths[group]; ok && current >= depth && group != allGroup {
+			return nil
+		}
+
+		depths[group] = depth
+		visiting[group] = true
+
+		for _, child := range i.Groups[group].Children {
+			err := visit(child, depth+1, append(slices.Clip(path), group))
+			if err != nil {
+				return err
+			}
+		}
+
+		visiting[group] = false
+
+		return nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(i.Groups)) {
+		if name == allGroup {
+			continue
+		}
+
+		hasParent := false
+		for _, group := range i.Groups {
+			if group.Name != allGroup && slices.Contains(group.Children, name) {
+				hasParent = true
+			}
+		}
+
+		if !hasParent {
+			err := visit(name, 1, []string{})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Groups which are only reachable through a cycle.
+	for _, name := range slices.Sorted(maps.Keys(i.Groups)) {
+		if _, ok := depths[name]; !ok {
+			err := visit(name, 1, []string{})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return depths, nil
+}
+
+// parseYAML reads the layout of YAML inventories: {group: {hosts: {host: vars}, vars: {}, children: {group: ...}}}.
+func (i *Inventory) parseYAML(content []byte) error {
+	var decoded map[string]any
+
+	err := yaml.Unmarshal(content, &decoded)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidInventory, err)
+	}
+
+	for name, definition := range decoded {
+		if name == "_meta" {
+			continue
+		}
+
+		err := i.parseYAMLGroup(name, definition)
+		if err != nil {
+			return err
+		}
+	}
+
+	meta, _ := stringKeys(decoded["_meta"]).(map[string]any)
+	hostVars, _ := stringKeys(meta["hostvars"]).(map[string]any)
+
+	for host, vars := range hostVars {
+		vars, _ := stringKeys(vars).(map[string]any)
+		i.addHost(host, "", vars)
+	}
+
+	return nil
+}
+
+func (i *Inventory) parseYAMLGroup(name string, definition any) error {
+	i.group(name)
+
+	fields := map[string]any{}
+
+	switch typed := stringKeys(definition).(type) {
+	case nil:
+	case map[string]any:
+		fields = typed
+	case []any:
+		// 'ansible-inventory --list' style group, a list of hosts.
+		fields["hosts"] = typed
+	default:
+		return fmt.Errorf("%w: group %q is not a mapping", errInvalidInventory, name)
+	}
+
+	switch hosts := fields["hosts"].(type) {
+	case map[string]any:
+		for pattern, vars := range hosts {
+			vars, _ := vars.(map[string]any)
+
+			err := i.addHostPattern(pattern, name, vars)
+			if err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, pattern := range hosts {
+			err := i.addHostPattern(fmt.Sprint(pattern), name, nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if vars, ok := fields["vars"].(map[string]any); ok {
+		maps.Copy(i.group(name).Vars, vars)
+	}
+
+	switch children := fields["children"].(type) {
+	case map[string]any:
+		for child, childDefinition := range children {
+			i.addChild(name, child)
+
+			err := i.parseYAMLGroup(child, childDefinition)
+			if err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range children {
+			i.addChild(name, fmt.Sprint(child))
+		}
+	}
+
+	return nil
+}
+
+// stringKeys converts the mappings decoded from YAML with non-string keys, so that they can be JSON encoded.
+func stringKeys(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, element := range typed {
+			typed[key] = stringKeys(element)
+		}
+
+		return typed
+	case map[any]any:
+		converted := make(map[string]any, len(typed))
+		for key, element := range typed {
+			converted[fmt.Sprint(key)] = stringKeys(element)
+		}
+
+		return converted
+	case []any:
+		for index, element := range typed {
+			typed[index] = stringKeys(element)
+		}
+
+		return typed
+	default:
+		return value
+	}
+}
+
+func (i *Inventory) addHostPattern(pattern string, group string, vars map[string]any) error {
+	hosts, port, err := expandHostPattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	// The port of the pattern comes before the variables, so that an explicit 'ansible_port' wins.
+	if port != "" {
+		number, _ := strconv.Atoi(port)
+		withPort := map[string]any{"ansible_port": number}
+		maps.Copy(withPort, vars)
+		vars = withPort
+	}
+
+	for _, host := range hosts {
+		i.addHost(host, group, vars)
+	}
+
+	return nil
+}
+
+var (
+	hostRange         = regexp.MustCompile(`\[([0-9]*|[a-zA-Z]):([0-9]+|[a-zA-Z])(?::([0-9]+))?\]`)
+	bracketedHostPort = regexp.MustCompile(`^\[([^\]]+)\]:([0-9]+)$`)
+)
+
+// expandHostPattern expands the ranges of a host pattern, e.g. 'web[01:03].example.com' or 'db-[a:c]',
+// and splits its port, e.g. 'host.example.com:2222' or '[2001:db8::1]:2222'. IPv6 addresses without
+// brackets, e.g. '2001:db8::1', have no port.
+func expandHostPattern(pattern string) ([]string, string, error) {
+	if match := bracketedHostPort.FindStringSubmatch(pattern); match != nil && !hostRange.MatchString("["+match[1]+"]") {
+		return []string{match[1]}, match[2], nil
+	}
+
+	port := ""
+
+	if index := strings.LastIndexByte(pattern, ':'); index > 0 {
+		candidate := pattern[index+1:]
+		rest := pattern[:index]
+
+		_, err := strconv.Atoi(candidate)
+		if err == nil && !strings.ContainsAny(hostRange.ReplaceAllString(rest, ""), ":[]") {
+			pattern, port = rest, candidate
+		}
+	}
+
+	location := hostRange.FindStringSubmatchIndex(pattern)
+	if location == nil {
+		if strings.ContainsAny(pattern, "[]") {
+			return nil, "", fmt.Errorf("%w %q", errInvalidHostPattern, pattern)
+		}
+
+		return []string{pattern}, port, nil
+	}
+
+	prefix, suffix := pattern[:location[0]], pattern[location[1]:]
+	begin, end := pattern[location[2]:location[3]], pattern[location[4]:location[5]]
+
+	stride := 1
+	if location[6] >= 0 {
+		stride, _ = strconv.Atoi(pattern[location[6]:location[7]])
+	}
+
+	values, err := expandRange(begin, end, stride)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w %q: %w", errInvalidHostPattern, pattern, err)
+	}
+
+	hosts := []string{}
+
+	for _, value := range values {
+		expanded, _, err := expandHostPattern(prefix + value + suffix)
+		if err != nil {
+			return nil, "", err
+		}
+
+		hosts = append(hosts, expanded...)
+	}
+
+	return hosts, port, nil
+}
+
+var errInvalidRange = errors.New("invalid range")
+
+func expandRange(begin string, end string, stride int) ([]string, error) {
+	if stride <= 0 {
+		return nil, fmt.Errorf("%w: the stride must be positive", errInvalidRange)
+	}
+
+	values := []string{}
+
+	beginNumber, beginErr := strconv.Atoi(orDefault(begin, "0"))
+	endNumber, endErr := strconv.Atoi(end)
+
+	switch {
+	case beginErr == nil && endErr == nil:
+		format := "%d"
+		if len(begin) > 1 && begin[0] == '0' {
+			format = fmt.Sprintf("%%0%dd", len(begin))
+		}
+
+		for value := beginNumber; value <= endNumber; value += stride {
+			values = append(values, fmt.Sprintf(format, value))
+		}
+	case len(begin) == 1 && len(end) == 1 && beginErr != nil && endErr != nil:
+		for value := begin[0]; value <= end[0]; value += byte(stride) {
+			values = append(values, string(value))
+		}
+	default:
+		return nil, fmt.Errorf("%w [%s:%s]", errInvalidRange, begin, end)
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w [%s:%s]: the range is empty", errInvalidRange, begin, end)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,300 @@
+package providerutils_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandHostPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		hosts   []string
+		port    string
+	}{
+		{pattern: "web.example.com", hosts: []string{"web.example.com"}},
+		{pattern: "web[1:3]", hosts: []string{"web1", "web2", "web3"}},
+		{pattern: "web[01:10:3].example.com", hosts: []string{"web01.example.com", "web04.example.com", "web07.example.com", "web10.example.com"}},
+		{pattern: "web[:2]", hosts: []string{"web0", "web1", "web2"}},
+		{pattern: "db-[a:c]", hosts: []string{"db-a", "db-b", "db-c"}},
+		{pattern: "db-[a:e:2]", hosts: []string{"db-a", "db-c", "db-e"}},
+		{pattern: "[1:2]-[a:b]", hosts: []string{"1-a", "1-b", "2-a", "2-b"}},
+		{pattern: "web.example.com:2222", hosts: []string{"web.example.com"}, port: "2222"},
+		{pattern: "192.0.2.1:22", hosts: []string{"192.0.2.1"}, port: "22"},
+		{pattern: "web[01:02]:2222", hosts: []string{"web01", "web02"}, port: "2222"},
+		{pattern: "web:ssh", hosts: []string{"web:ssh"}},
+		{pattern: "fe80::1", hosts: []string{"fe80::1"}},
+		{pattern: "2001:db8::10", hosts: []string{"2001:db8::10"}},
+		{pattern: "[2001:db8::10]:2222", hosts: []string{"2001:db8::10"}, port: "2222"},
+		{pattern: "[1:2]:2222", hosts: []string{"1", "2"}, port: "2222"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			t.Parallel()
+
+			hosts, port, err := providerutils.ExpandHostPattern(test.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, test.hosts, hosts)
+			assert.Equal(t, test.port, port)
+		})
+	}
+}
+
+func TestExpandHostPatternInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, pattern := range []string{"web[3:1]", "web[a:10]", "web[ab:c]", "web[1:3", "web1:3]", "web[1:3:0]", "[fe80::1]"} {
+		t.Run(pattern, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := providerutils.ExpandHostPattern(pattern)
+			require.ErrorIs(t, err, providerutils.ErrInvalidHostPattern)
+		})
+	}
+}
+
+func TestParseInventoryINI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		// hosts are the expected variables of every host, as merged by HostVars.
+		hosts map[string]map[string]any
+		// groups are the expected groups of some hosts, as returned by HostGroups.
+		groups map[string][]string
+	}{
+		{
+			name: "ranges and ports",
+			content: `web[01:03:2].example.com ansible_user=deploy
+db-[a:b]:2222
+fe80::1
+192.0.2.1:22 ansible_port=2200
+`,
+			hosts: map[string]map[string]any{
+				"web01.example.com": {"ansible_user": "deploy"},
+				"web03.example.com": {"ansible_user": "deploy"},
+				"db-a":              {"ansible_port": 2222},
+				"db-b":              {"ansible_port": 2222},
+				"fe80::1":           {},
+				"192.0.2.1":         {"ansible_port": int64(2200)},
+			},
+		},
+		{
+			name: "vars and children sections",
+			content: `[web]
+web1
+
+[db]
+db1 role=primary
+
+[prod:children]
+web
+db
+
+[prod:vars]
+env = prod
+role = none
+
+[web:vars]
+role=web
+`,
+			hosts: map[string]map[string]any{
+				"web1": {"env": "prod", "role": "web"},
+				"db1":  {"env": "prod", "role": "primary"},
+			},
+			groups: map[string][]string{
+				"web1": {"prod", "web"},
+				"db1":  {"db", "prod"},
+			},
+		},
+		{
+			name: "quoted and commented lines",
+			content: `# A comment
+; Another comment
+[web] # A section comment
+web1 motd="hello world" path='/srv/a b' # A host comment
+web2 # Only a comment
+web3 escaped=a\ b empty=""
+   # An indented comment
+"web4" key="quoted \"value\""
+`,
+			hosts: map[string]map[string]any{
+				"web1": {"motd": "hello world", "path": "/srv/a b"},
+				"web2": {},
+				"web3": {"escaped": "a b", "empty": ""},
+				"web4": {"key": `quoted "value"`},
+			},
+		},
+		{
+			name: "literal evaluation",
+			content: `host1 yes=True int=1 float=1.5 none=None list="[1, 2]" quoted="'a b'" word=yes dict="{'a': (1, 'b')}"
+
+[all:vars]
+list = [1, 2]
+quoted = 'a b'
+escaped = 'a\tb'
+negative = -3
+octal = 0755
+hostname = web.example.com
+unterminated = 'a b
+`,
+			hosts: map[string]map[string]any{
+				"host1": {
+					"yes":          true,
+					"int":          int64(1),
+					"float":        1.5,
+					"none":         nil,
+					"list":         []any{int64(1), int64(2)},
+					"quoted":       "a b",
+					"word":         "yes",
+					"dict":         map[string]any{"a": []any{int64(1), "b"}},
+					"escaped":      "a\tb",
+					"negative":     int64(-3),
+					"octal":        "0755",
+					"hostname":     "web.example.com",
+					"unterminated": "'a b",
+				},
+			},
+		},
+		{
+			name: "parents before children",
+			content: `[all:vars]
+level=all
+from_all=all
+
+[parent:children]
+child
+
+[parent:vars]
+level=parent
+from_parent=parent
+
+[aaa]
+host1
+
+[aaa:vars]
+level=aaa
+
+[child]
+host1
+host2 level=host
+
+[child:vars]
+level=child
+
+[alpha]
+host2
+
+[alpha:vars]
+same_depth=alpha
+
+[beta]
+host2
+
+[beta:vars]
+same_depth=beta
+`,
+			hosts: map[string]map[string]any{
+				"host1": {"level": "child", "from_all": "all", "from_parent": "parent"},
+				"host2": {"level": "host", "from_all": "all", "from_parent": "parent", "same_depth": "beta"},
+			},
+			groups: map[string][]string{
+				"host1": {"aaa", "child", "parent"},
+				"host2": {"alpha", "beta", "child", "parent"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			inventory, err := providerutils.ParseInventory([]byte(test.content), providerutils.InventoryFormatINI)
+			require.NoError(t, err)
+
+			assert.Equal(t, slices.Sorted(maps.Keys(test.hosts)), slices.Sorted(maps.Keys(inventory.Hosts)))
+
+			for name, vars := range test.hosts {
+				assert.Equal(t, vars, inventory.HostVars(name), name)
+			}
+
+			for name, groups := range test.groups {
+				assert.Equal(t, groups, inventory.HostGroups(name), name)
+			}
+		})
+	}
+}
+
+func TestParseInventoryINIInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		err     error
+	}{
+		{name: "cycle", content: "[a:children]\nb\n[b:children]\nc\n[c:children]\na\n", err: providerutils.ErrCyclicGroups},
+		{name: "own child", content: "[a:children]\na\n", err: providerutils.ErrCyclicGroups},
+		{name: "semicolon after a section", content: "[web] ; comment\nweb1\n", err: providerutils.ErrInvalidINILine},
+		{name: "text after a section", content: "[web] web1\n", err: providerutils.ErrInvalidINILine},
+		{name: "unclosed section", content: "[web\nweb1\n", err: providerutils.ErrInvalidINILine},
+		{name: "unknown section type", content: "[web:hosts_vars]\nweb1\n", err: providerutils.ErrInvalidSectionType},
+		{name: "group variable without a value", content: "[web:vars]\nrole\n", err: providerutils.ErrInvalidINILine},
+		{name: "host variable without a value", content: "web1 role\n", err: providerutils.ErrInvalidINILine},
+		{name: "unterminated quote", content: "web1 motd='hello\n", err: providerutils.ErrInvalidINILine},
+		{name: "invalid range", content: "web[3:1]\n", err: providerutils.ErrInvalidHostPattern},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := providerutils.ParseInventory([]byte(test.content), providerutils.InventoryFormatINI)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestParseInventoryYAML(t *testing.T) {
+	t.Parallel()
+
+	inventory, err := providerutils.ParseInventory([]byte(`
+all:
+  vars:
+    level: all
+  children:
+    prod:
+      vars:
+        level: prod
+      children:
+        web:
+          hosts:
+            web[01:02]:
+              ansible_user: deploy
+            "[2001:db8::10]:2222":
+          vars:
+            level: web
+`), providerutils.InventoryFormatYAML)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2001:db8::10", "web01", "web02"}, slices.Sorted(maps.Keys(inventory.Hosts)))
+	assert.Equal(t, map[string]any{"level": "web", "ansible_user": "deploy"}, inventory.HostVars("web01"))
+	assert.Equal(t, map[string]any{"level": "web", "ansible_port": 2222}, inventory.HostVars("2001:db8::10"))
+	assert.Equal(t, []string{"prod", "web"}, inventory.HostGroups("web01"))
+}
+
+func TestParseInventoryYAMLCycle(t *testing.T) {
+	t.Parallel()
+
+	_, err := providerutils.ParseInventory([]byte(`{"a": {"children": ["b"]}, "b": {"children": ["a"]}}`),
+		providerutils.InventoryFormatJSON)
+	require.ErrorIs(t, err, providerutils.ErrCyclicGroups)
+}
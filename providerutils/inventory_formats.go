@@ -0,0 +1,393 @@
+package providerutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errInvalidInventory = errors.New("invalid inventory")
+
+const yamlIndent = 2
+
+// decodeInventory decodes a JSON inventory, keeping its numbers as they are.
+func decodeInventory(inventory []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(inventory))
+	decoder.UseNumber()
+
+	var decoded map[string]any
+
+	err := decoder.Decode(&decoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidInventory, err)
+	}
+
+	return decoded, nil
+}
+
+// CanonicalJSON re-encodes a JSON document with sorted keys, no insignificant whitespace, no HTML escaping
+// and its numbers as they are, so that equal documents are always encoded byte for byte the same.
+func CanonicalJSON(document []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var decoded any
+
+	err := decoder.Decode(&decoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidInventory, err)
+	}
+
+	return canonicalJSON(decoded)
+}
+
+func canonicalJSON(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	// Maps are encoded with sorted keys.
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	err := encoder.Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't encode to JSON: %w", err)
+	}
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// InventoryListJSON converts a JSON inventory, as built by the ansible_inventory data source, into the
+// format of 'ansible-inventory --list', which inventory scripts print: groups list their hosts and children,
+// the variables of all the hosts are in '_meta.hostvars', 'all' lists the top level groups and 'ungrouped'
+// the hosts without any other group. It is encoded like CanonicalJSON.
+func InventoryListJSON(inventory []byte) (string, error) {
+	decoded, err := decodeInventory(inventory)
+	if err != nil {
+		return "", err
+	}
+
+	groups := map[string]*iniGroup{}
+	for name, definition := range decoded {
+		flattenINIGroup(groups, name, definition)
+	}
+
+	hostVars := map[string]any{}
+	grouped := map[string]bool{}
+	isChild := map[string]bool{}
+
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		for host, vars := range groups[name].hosts {
+			merged, _ := hostVars[host].(map[string]any)
+			if merged == nil {
+				merged = map[string]any{}
+			}
+
+			maps.Copy(merged, vars)
+			hostVars[host] = merged
+
+			if name != allGroup && name != ungroupedGroup {
+				grouped[host] = true
+			}
+		}
+
+		// The children of 'all' are top level groups.
+		for child := range groups[name].children {
+			isChild[child] = isChild[child] || name != allGroup
+		}
+	}
+
+	list := map[string]any{"_meta": map[string]any{"hostvars": hostVars}}
+
+	allChildren := []string{ungroupedGroup}
+	ungrouped := []string{}
+
+	for name, group := range groups {
+		if name == allGroup || name == ungroupedGroup {
+			continue
+		}
+
+		if !isChild[name] {
+			allChildren = append(allChildren, name)
+		}
+
+		list[name] = listGroup(slices.Sorted(maps.Keys(group.hosts)), group)
+	}
+
+	for host := range hostVars {
+		if !grouped[host] {
+			ungrouped = append(ungrouped, host)
+		}
+	}
+
+	slices.Sort(allChildren)
+	slices.Sort(ungrouped)
+
+	all := listGroup(nil, groups[allGroup])
+	all["children"] = allChildren
+	list[allGroup] = all
+	list[ungroupedGroup] = listGroup(ungrouped, groups[ungroupedGroup])
+
+	encoded, err := canonicalJSON(list)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// listGroup renders a group in the format of 'ansible-inventory --list'. Empty fields are left out.
+func listGroup(hosts []string, group *iniGroup) map[string]any {
+	rendered := map[string]any{}
+
+	if len(hosts) > 0 {
+		rendered["hosts"] = hosts
+	}
+
+	if group == nil {
+		return rendered
+	}
+
+	if len(group.vars) > 0 {
+		rendered["vars"] = group.vars
+	}
+
+	if len(group.children) > 0 {
+		rendered["children"] = slices.Sorted(maps.Keys(group.children))
+	}
+
+	return rendered
+}
+
+// InventoryYAML converts a JSON inventory, as built by the ansible_inventory data source,
+// into the YAML inventory format. Keys are sorted, so that the output is stable.
+func InventoryYAML(inventory []byte) (string, error) {
+	decoded, err := decodeInventory(inventory)
+	if err != nil {
+		return "", err
+	}
+
+	var buffer bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(yamlIndent)
+
+	err = encoder.Encode(yamlValue(decoded))
+	if err != nil {
+		return "", fmt.Errorf("couldn't encode inventory to YAML: %w", err)
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return "", fmt.Errorf("couldn't encode inventory to YAML: %w", err)
+	}
+
+	return buffer.String(), nil
+}
+
+// yamlValue converts the JSON numbers into integers or floats, so that they aren't quoted.
+func yamlValue(value any) any {
+	switch typed := value.(type) {
+	case json.Number:
+		if integer, err := typed.Int64(); err == nil {
+			return integer
+		}
+
+		if float, err := typed.Float64(); err == nil {
+			return float
+		}
+
+		return typed.String()
+	case map[string]any:
+		converted := make(map[string]any, len(typed))
+		for key, element := range typed {
+			converted[key] = yamlValue(element)
+		}
+
+		return converted
+	case []any:
+		converted := make([]any, 0, len(typed))
+		for _, element := range typed {
+			converted = append(converted, yamlValue(element))
+		}
+
+		return converted
+	default:
+		return value
+	}
+}
+
+// iniGroup is a group of an INI inventory, where every group has its own sections.
+type iniGroup struct {
+	hosts    map[string]map[string]any
+	vars     map[string]any
+	children map[string]bool
+}
+
+// InventoryINI converts a JSON inventory, as built by the ansible_inventory data source,
+// into the INI inventory format: nested groups become [group:children] sections.
+// Groups, hosts and variables are sorted, so that the output is stable.
+func InventoryINI(inventory []byte) (string, error) {
+	decoded, err := decodeInventory(inventory)
+	if err != nil {
+		return "", err
+	}
+
+	groups := map[string]*iniGroup{}
+	for name, definition := range decoded {
+		flattenINIGroup(groups, name, definition)
+	}
+
+	var builder strings.Builder
+
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		group := groups[name]
+
+		if len(group.hosts) > 0 || (len(group.vars) == 0 && len(group.children) == 0) {
+			builder.WriteString("[" + name + "]\n")
+
+			for _, host := range slices.Sorted(maps.Keys(group.hosts)) {
+				builder.WriteString(host)
+
+				for _, key := range slices.Sorted(maps.Keys(group.hosts[host])) {
+					builder.WriteString(" " + key + "=" + iniHostValue(group.hosts[host][key]))
+				}
+
+				builder.WriteString("\n")
+			}
+
+			builder.WriteString("\n")
+		}
+
+		if len(group.vars) > 0 {
+			builder.WriteString("[" + name + ":vars]\n")
+
+			for _, key := range slices.Sorted(maps.Keys(group.vars)) {
+				builder.WriteString(key + "=" + iniValue(group.vars[key]) + "\n")
+			}
+
+			builder.WriteString("\n")
+		}
+
+		if len(group.children) > 0 {
+			builder.WriteString("[" + name + ":children]\n")
+
+			for _, child := range slices.Sorted(maps.Keys(group.children)) {
+				builder.WriteString(child + "\n")
+			}
+
+			builder.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n"), nil
+}
+
+// flattenINIGroup merges a group definition, and the ones of its nested children, into the flat groups.
+func flattenINIGroup(groups map[string]*iniGroup, name string, definition any) {
+	group, ok := groups[name]
+	if !ok {
+		group = &iniGroup{hosts: map[string]map[string]any{}, vars: map[string]any{}, children: map[string]bool{}}
+		groups[name] = group
+	}
+
+	fields, _ := definition.(map[string]any)
+
+	hosts, _ := fields["hosts"].(map[string]any)
+	for host, hostVars := range hosts {
+		if _, ok := group.hosts[host]; !ok {
+			group.hosts[host] = map[string]any{}
+		}
+
+		if hostVars, ok := hostVars.(map[string]any); ok {
+			maps.Copy(group.hosts[host], hostVars)
+		}
+	}
+
+	if vars, ok := fields["vars"].(map[string]any); ok {
+		maps.Copy(group.vars, vars)
+	}
+
+	children, _ := fields["children"].(map[string]any)
+	for child, childDefinition := range children {
+		group.children[child] = true
+		flattenINIGroup(groups, child, childDefinition)
+	}
+}
+
+// plainINIValue matches the strings which are written without quotes. Ansible evaluates INI values
+// as Python literals, so that numbers and True/False/None are quoted to stay strings.
+var plainINIValue = regexp.MustCompile(`^[A-Za-z_/@%][A-Za-z0-9_./:@%+-]*$`)
+
+// iniValue renders a value of a [group:vars] section as a Python literal.
+func iniValue(value any) string {
+	if str, ok := value.(string); ok && plainINIValue.MatchString(str) && !slices.Contains(
+		[]string{"True", "False", "None"}, str,
+	) {
+		return str
+	}
+
+	return pythonLiteral(value)
+}
+
+// iniHostValue renders a host variable of a host line, which is split like a shell command line.
+func iniHostValue(value any) string {
+	rendered := iniValue(value)
+	if plainINIValue.MatchString(rendered) || isPythonNumber(value) {
+		return rendered
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(rendered) + `"`
+}
+
+func isPythonNumber(value any) bool {
+	_, ok := value.(json.Number)
+
+	return ok
+}
+
+// pythonLiteral renders a JSON value as a Python literal, which Ansible evaluates back to the same value.
+func pythonLiteral(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if typed {
+			return "True"
+		}
+
+		return "False"
+	case json.Number:
+		return typed.String()
+	case string:
+		return "'" + strings.NewReplacer(
+			`\`, `\\`,
+			`'`, `\'`,
+			"\n", `\n`,
+			"\r", `\r`,
+			"\t", `\t`,
+		).Replace(typed) + "'"
+	case []any:
+		elements := make([]string, 0, len(typed))
+		for _, element := range typed {
+			elements = append(elements, pythonLiteral(element))
+		}
+
+		return "[" + strings.Join(elements, ", ") + "]"
+	case map[string]any:
+		elements := make([]string, 0, len(typed))
+		for _, key := range slices.Sorted(maps.Keys(typed)) {
+			elements = append(elements, pythonLiteral(key)+": "+pythonLiteral(typed[key]))
+		}
+
+		return "{" + strings.Join(elements, ", ") + "}"
+	default:
+		return pythonLiteral(fmt.Sprint(value))
+	}
+}
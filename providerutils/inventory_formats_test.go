@@ -0,0 +1,140 @@
+package providerutils_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// determinismRuns is the number of times an encoder is run to check that its output is stable,
+// despite the random iteration order of Go maps.
+const determinismRuns = 20
+
+func readTestData(t *testing.T, name string) []byte {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "inventory", name))
+	require.NoError(t, err)
+
+	return content
+}
+
+func canonicalJSONString(document []byte) (string, error) {
+	encoded, err := providerutils.CanonicalJSON(document)
+
+	return string(encoded), err
+}
+
+func TestInventoryEncoders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		encode   func([]byte) (string, error)
+		expected string
+	}{
+		{name: "YAML", encode: providerutils.InventoryYAML, expected: "inventory.yml"},
+		{name: "INI", encode: providerutils.InventoryINI, expected: "inventory.ini"},
+		{name: "list JSON", encode: providerutils.InventoryListJSON, expected: "inventory_list.json"},
+		{name: "canonical JSON", encode: canonicalJSONString, expected: "inventory_canonical.json"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			inventory := readTestData(t, "inventory.json")
+			expected := string(readTestData(t, test.expected))
+
+			for range determinismRuns {
+				encoded, err := test.encode(inventory)
+				require.NoError(t, err)
+				assert.Equal(t, expected, encoded)
+			}
+		})
+	}
+}
+
+func TestInventoryEncodersInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, encode := range []func([]byte) (string, error){
+		providerutils.InventoryYAML,
+		providerutils.InventoryINI,
+		providerutils.InventoryListJSON,
+	} {
+		_, err := encode([]byte(`["not", "an", "inventory"]`))
+		assert.Error(t, err)
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{name: "sorted keys", document: `{"b": 1, "a": {"d": 2, "c": 3}}`, want: `{"a":{"c":3,"d":2},"b":1}`},
+		{name: "numbers as they are", document: `[1.50, 1e3, 12345678901234567890]`, want: `[1.50,1e3,12345678901234567890]`},
+		{name: "no HTML escaping", document: `{"url": "http://a/?b=1&c=<d>"}`, want: `{"url":"http://a/?b=1&c=<d>"}`},
+		{name: "equal documents", document: "{\n  \"a\" : [ true, null ]\n}", want: `{"a":[true,null]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := providerutils.CanonicalJSON([]byte(test.document))
+			require.NoError(t, err)
+			assert.Equal(t, test.want, string(encoded))
+		})
+	}
+
+	_, err := providerutils.CanonicalJSON([]byte(`{"a":`))
+	assert.Error(t, err)
+}
+
+func TestPythonLiteral(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "string", value: `"web"`, want: `'web'`},
+		{name: "single quotes", value: `"it's"`, want: `'it\'s'`},
+		{name: "double quotes", value: `"say \"hi\""`, want: `'say "hi"'`},
+		{name: "backslashes", value: `"C:\\temp"`, want: `'C:\\temp'`},
+		{name: "newlines and tabs", value: `"a\nb\r\tc"`, want: `'a\nb\r\tc'`},
+		{name: "integer", value: `42`, want: `42`},
+		{name: "float", value: `4096.5`, want: `4096.5`},
+		{name: "true", value: `true`, want: `True`},
+		{name: "false", value: `false`, want: `False`},
+		{name: "null", value: `null`, want: `None`},
+		{name: "list", value: `[1, "two", [true]]`, want: `[1, 'two', [True]]`},
+		{name: "dict with sorted keys", value: `{"b": 1, "a": {"it's": null}}`, want: `{'a': {'it\'s': None}, 'b': 1}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var value any
+
+			decoder := json.NewDecoder(strings.NewReader(test.value))
+			decoder.UseNumber()
+			require.NoError(t, decoder.Decode(&value))
+
+			assert.Equal(t, test.want, providerutils.PythonLiteral(value))
+		})
+	}
+}
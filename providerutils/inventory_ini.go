@@ -0,0 +1,423 @@
+package providerutils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	errInvalidINILine      = errors.New("invalid INI inventory line")
+	errInvalidSectionType  = errors.New("invalid INI inventory section type")
+	errUnterminatedQuote   = errors.New("no closing quotation")
+	errInvalidPythonSyntax = errors.New("invalid Python literal")
+)
+
+const (
+	sectionHosts    = "hosts"
+	sectionVars     = "vars"
+	sectionChildren = "children"
+)
+
+// parseINI reads an INI inventory, like the ini inventory plugin of Ansible: [group] sections list
+// host patterns with their variables, [group:vars] sections group variables and [group:children]
+// sections child groups. Hosts listed before the first section are ungrouped.
+//
+// It isn't parsed with gopkg.in/ini.v1: host lines aren't key=value pairs, but a host pattern followed by
+// variables split like a shell command line, with values evaluated as Python literals.
+func (i *Inventory) parseINI(content string) error {
+	group, section := "", sectionHosts
+
+	for number, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if isINIComment(line) {
+			continue
+		}
+
+		err := i.parseINILine(line, &group, &section)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", number+1, err)
+		}
+	}
+
+	return nil
+}
+
+// isINIComment tells whether a trimmed line is empty or a comment.
+func isINIComment(line string) bool {
+	return line == "" || line[0] == '#' || line[0] == ';'
+}
+
+// isSectionEnd tells whether the trimmed end of a section header line is empty or a comment.
+// Unlike at the start of a line, ';' doesn't start a comment there, like in Ansible.
+func isSectionEnd(rest string) bool {
+	return rest == "" || rest[0] == '#'
+}
+
+func (i *Inventory) parseINILine(line string, group *string, section *string) error {
+	if strings.HasPrefix(line, "[") {
+		end := strings.IndexByte(line, ']')
+		if end < 0 || !isSectionEnd(strings.TrimSpace(line[end+1:])) {
+			return fmt.Errorf("%w %q", errInvalidINILine, line)
+		}
+
+		name, kind, found := strings.Cut(line[1:end], ":")
+		if !found {
+			kind = sectionHosts
+		}
+
+		if kind != sectionHosts && kind != sectionVars && kind != sectionChildren {
+			return fmt.Errorf("%w %q in %q, expected %q or %q", errInvalidSectionType, kind, line, sectionVars, sectionChildren)
+		}
+
+		*group, *section = strings.TrimSpace(name), kind
+		i.group(*group)
+
+		return nil
+	}
+
+	switch *section {
+	case sectionVars:
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return fmt.Errorf("%w %q, expected a key=value variable definition", errInvalidINILine, line)
+		}
+
+		i.group(*group).Vars[strings.TrimSpace(key)] = iniVariable(strings.TrimSpace(value))
+	case sectionChildren:
+		i.addChild(*group, line)
+	default:
+		tokens, err := shellSplit(line)
+		if err != nil {
+			return fmt.Errorf("%w %q: %w", errInvalidINILine, line, err)
+		}
+
+		if len(tokens) == 0 {
+			return nil
+		}
+
+		vars := map[string]any{}
+
+		for _, token := range tokens[1:] {
+			key, value, found := strings.Cut(token, "=")
+			if !found {
+				return fmt.Errorf("%w %q, expected key=value host variable assignments", errInvalidINILine, line)
+			}
+
+			vars[key] = iniVariable(value)
+		}
+
+		return i.addHostPattern(tokens[0], *group, vars)
+	}
+
+	return nil
+}
+
+// iniVariable evaluates a variable of an INI inventory as a Python literal, like Ansible does.
+// Values which aren't literals, e.g. 'yes' or 'web.example.com', stay strings.
+func iniVariable(value string) any {
+	parser := &pythonParser{input: []rune(value)}
+
+	parsed, err := parser.parseLiteral()
+	if err != nil {
+		return value
+	}
+
+	parser.skipSpaces()
+
+	if parser.position != len(parser.input) {
+		return value
+	}
+
+	return parsed
+}
+
+// shellSplit splits a host line like Python's shlex.split(line, comments=True) does, which Ansible uses.
+func shellSplit(line string) ([]string, error) {
+	tokens := []string{}
+
+	var token strings.Builder
+
+	inToken := false
+	runes := []rune(line)
+
+	for index := 0; index < len(runes); index++ {
+		char := runes[index]
+
+		switch {
+		case unicode.IsSpace(char):
+			if inToken {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				inToken = false
+			}
+		case char == '#':
+			if inToken {
+				tokens = append(tokens, token.String())
+			}
+
+			return tokens, nil
+		case char == '\\':
+			inToken = true
+
+			if index+1 < len(runes) {
+				index++
+				token.WriteRune(runes[index])
+			}
+		case char == '\'' || char == '"':
+			inToken = true
+
+			closed := false
+
+			for index++; index < len(runes); index++ {
+				if runes[index] == char {
+					closed = true
+
+					break
+				}
+
+				// In double quotes, a backslash only escapes a double quote or another backslash.
+				if char == '"' && runes[index] == '\\' && index+1 < len(runes) &&
+					(runes[index+1] == '"' || runes[index+1] == '\\') {
+					index++
+				}
+
+				token.WriteRune(runes[index])
+			}
+
+			if !closed {
+				return nil, errUnterminatedQuote
+			}
+		default:
+			inToken = true
+
+			token.WriteRune(char)
+		}
+	}
+
+	if inToken {
+		tokens = append(tokens, token.String())
+	}
+
+	return tokens, nil
+}
+
+// pythonParser parses the Python literals Ansible evaluates in INI inventories: strings, numbers,
+// True, False, None, lists, tuples and dicts.
+type pythonParser struct {
+	input    []rune
+	position int
+}
+
+func (p *pythonParser) skipSpaces() {
+	for p.position < len(p.input) && unicode.IsSpace(p.input[p.position]) {
+		p.position++
+	}
+}
+
+func (p *pythonParser) peek() rune {
+	if p.position < len(p.input) {
+		return p.input[p.position]
+	}
+
+	return 0
+}
+
+func (p *pythonParser) parseLiteral() (any, error) {
+	p.skipSpaces()
+
+	switch char := p.peek(); {
+	case char == '\'' || char == '"':
+		return p.parseString()
+	case char == '[':
+		return p.parseSequence(']')
+	case char == '(':
+		return p.parseSequence(')')
+	case char == '{':
+		return p.parseDict()
+	case char == '-' || char == '+' || char == '.' || unicode.IsDigit(char):
+		return p.parseNumber()
+	case unicode.IsLetter(char):
+		return p.parseName()
+	default:
+		return nil, errInvalidPythonSyntax
+	}
+}
+
+func (p *pythonParser) parseName() (any, error) {
+	start := p.position
+	for p.position < len(p.input) && (unicode.IsLetter(p.input[p.position]) || unicode.IsDigit(p.input[p.position]) ||
+		p.input[p.position] == '_') {
+		p.position++
+	}
+
+	switch string(p.input[start:p.position]) {
+	case "True":
+		return true, nil
+	case "False":
+		return false, nil
+	case "None":
+		return nil, nil
+	default:
+		return nil, errInvalidPythonSyntax
+	}
+}
+
+func (p *pythonParser) parseNumber() (any, error) {
+	start := p.position
+	for p.position < len(p.input) && strings.ContainsRune("+-.0123456789_eExXoObBabcdefABCDEF", p.input[p.position]) {
+		// A sign is only part of the number at its start or in an exponent.
+		if (p.input[p.position] == '-' || p.input[p.position] == '+') && p.position > start &&
+			!strings.ContainsRune("eE", p.input[p.position-1]) {
+			break
+		}
+
+		p.position++
+	}
+
+	literal := string(p.input[start:p.position])
+
+	// Python has no octal literals with a leading zero, e.g. '0755' is not a number.
+	digits := strings.TrimLeft(literal, "+-")
+	if len(digits) > 1 && digits[0] == '0' && (unicode.IsDigit(rune(digits[1])) || digits[1] == '_') {
+		return nil, errInvalidPythonSyntax
+	}
+
+	integer, err := strconv.ParseInt(literal, 0, 64)
+	if err == nil {
+		return integer, nil
+	}
+
+	if strings.ContainsAny(literal, "xXoObB") {
+		return nil, errInvalidPythonSyntax
+	}
+
+	float, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return nil, errInvalidPythonSyntax
+	}
+
+	return float, nil
+}
+
+var pythonEscapes = map[rune]rune{
+	'\\': '\\', '\'': '\'', '"': '"', 'n': '\n', 'r': '\r', 't': '\t', 'a': '\a', 'b': '\b', 'f': '\f', 'v': '\v', '0': 0,
+}
+
+func (p *pythonParser) parseString() (any, error) {
+	quote := p.input[p.position]
+	p.position++
+
+	var builder strings.Builder
+
+	for p.position < len(p.input) {
+		char := p.input[p.position]
+		p.position++
+
+		switch {
+		case char == quote:
+			return builder.String(), nil
+		case char == '\\' && p.position < len(p.input):
+			escaped := p.input[p.position]
+			p.position++
+
+			if replacement, ok := pythonEscapes[escaped]; ok {
+				builder.WriteRune(replacement)
+			} else {
+				builder.WriteRune(char)
+				builder.WriteRune(escaped)
+			}
+		default:
+			builder.WriteRune(char)
+		}
+	}
+
+	return nil, errInvalidPythonSyntax
+}
+
+func (p *pythonParser) parseSequence(closing rune) (any, error) {
+	p.position++
+
+	elements := []any{}
+
+	for {
+		p.skipSpaces()
+
+		if p.peek() == closing {
+			p.position++
+
+			return elements, nil
+		}
+
+		element, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, element)
+
+		err = p.parseSeparator(closing)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *pythonParser) parseDict() (any, error) {
+	p.position++
+
+	dict := map[string]any{}
+
+	for {
+		p.skipSpaces()
+
+		if p.peek() == '}' {
+			p.position++
+
+			return dict, nil
+		}
+
+		key, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+
+		if p.peek() != ':' {
+			return nil, errInvalidPythonSyntax
+		}
+
+		p.position++
+
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+
+		dict[fmt.Sprint(key)] = value
+
+		err = p.parseSeparator('}')
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseSeparator consumes the comma after an element of a collection, which is optional before its end.
+func (p *pythonParser) parseSeparator(closing rune) error {
+	p.skipSpaces()
+
+	switch p.peek() {
+	case ',':
+		p.position++
+
+		return nil
+	case closing:
+		return nil
+	default:
+		return errInvalidPythonSyntax
+	}
+}
@@ -0,0 +1,111 @@
+package providerutils_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildInventory(
+	t *testing.T,
+	hosts []providerutils.InventoryHost,
+	groups []providerutils.InventoryGroup,
+) map[string]any {
+	t.Helper()
+
+	path, err := providerutils.BuildInventory("inventory_test_*.json", hosts, groups)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, os.Remove(path))
+	})
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var inventory map[string]any
+
+	require.NoError(t, json.Unmarshal(content, &inventory))
+
+	return inventory
+}
+
+func TestBuildInventory(t *testing.T) {
+	t.Parallel()
+
+	inventory := buildInventory(t, []providerutils.InventoryHost{
+		{Name: "web-1", Groups: []string{"web", "eu"}, Vars: map[string]any{"ansible_port": 2222}},
+		{Name: "web-2", Groups: []string{"web"}},
+		{Name: "localhost"},
+	}, nil)
+
+	assert.Equal(t, map[string]any{
+		"web": map[string]any{
+			"hosts": map[string]any{
+				"web-1": map[string]any{"ansible_port": float64(2222)},
+				"web-2": map[string]any{},
+			},
+		},
+		"eu": map[string]any{
+			"hosts": map[string]any{"web-1": map[string]any{"ansible_port": float64(2222)}},
+		},
+		// Hosts without groups are in the default group.
+		providerutils.DefaultHostGroup: map[string]any{
+			"hosts": map[string]any{"localhost": map[string]any{}},
+		},
+	}, inventory)
+}
+
+// The variables of a host or group listed several times are merged, the last value wins.
+func TestBuildInventoryMergedVars(t *testing.T) {
+	t.Parallel()
+
+	inventory := buildInventory(t, []providerutils.InventoryHost{
+		{Name: "web-1", Groups: []string{"web"}, Vars: map[string]any{"ansible_user": "admin", "tier": "front"}},
+		{Name: "web-1", Groups: []string{"eu"}, Vars: map[string]any{"ansible_user": "deploy"}},
+	}, []providerutils.InventoryGroup{
+		{Name: "web", Vars: map[string]any{"http_port": 80}},
+		{Name: "web", Vars: map[string]any{"http_port": 8080, "tls": true}},
+	})
+
+	webHosts := map[string]any{"web-1": map[string]any{"ansible_user": "deploy", "tier": "front"}}
+
+	assert.Equal(t, map[string]any{
+		"web": map[string]any{
+			"hosts": webHosts,
+			"vars":  map[string]any{"http_port": float64(8080), "tls": true},
+		},
+		"eu": map[string]any{"hosts": webHosts},
+	}, inventory)
+}
+
+func TestBuildInventoryChildren(t *testing.T) {
+	t.Parallel()
+
+	inventory := buildInventory(t, []providerutils.InventoryHost{
+		{Name: "db-1", Groups: []string{"db"}},
+	}, []providerutils.InventoryGroup{
+		{Name: "production", Children: []string{"web", "db"}, Vars: map[string]any{"env": "production"}},
+		{Name: "production", Children: []string{"db"}},
+		{Name: "web"},
+	})
+
+	assert.Equal(t, map[string]any{
+		"production": map[string]any{
+			"children": map[string]any{"web": map[string]any{}, "db": map[string]any{}},
+			"vars":     map[string]any{"env": "production"},
+		},
+		"web": map[string]any{},
+		"db": map[string]any{
+			"hosts": map[string]any{"db-1": map[string]any{}},
+		},
+	}, inventory)
+}
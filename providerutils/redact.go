@@ -0,0 +1,162 @@
+package providerutils
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+)
+
+// RedactedValue replaces the secrets masked by a Redactor.
+const RedactedValue = "(sensitive value)"
+
+const executablePerm = 0o111
+
+// minPasswordVarLength is the length of the shortest password variable value masked by AddPasswordVars.
+// Shorter values, such as "1" or "yes", would be masked everywhere in the output, making it unreadable,
+// and are unlikely to be passwords. Secrets registered explicitly are always masked.
+const minPasswordVarLength = 4
+
+// passwordVarNames are the connection and privilege escalation password variables,
+// whose values are masked wherever they are set: in extra vars, host vars, group vars or inventories.
+var passwordVarNames = []string{
+	"ansible_password",
+	"ansible_ssh_pass",
+	"ansible_become_password",
+	"ansible_become_pass",
+	"ansible_ssh_private_key_passphrase",
+}
+
+// Redactor masks the values of secrets, such as sensitive extra vars, passwords and vault passwords,
+// in the command lines, logs, diagnostics and outputs of the Ansible CLIs.
+// A nil Redactor masks nothing.
+type Redactor struct {
+	secrets  []string
+	replacer *strings.Replacer
+}
+
+func NewRedactor() *Redactor {
+	return &Redactor{}
+}
+
+// AddSecrets registers secret values, however short they are. Empty values are ignored.
+func (r *Redactor) AddSecrets(secrets ...string) {
+	for _, secret := range secrets {
+		if secret == "" || slices.Contains(r.secrets, secret) {
+			continue
+		}
+
+		r.secrets = append(r.secrets, secret)
+		r.replacer = nil
+	}
+}
+
+// AddPasswordVars registers the values of the password variables (ansible_password, ansible_become_password, ...)
+// found at any depth of the given variables, so that whole inventories can be passed.
+// Short values are ignored, see minPasswordVarLength.
+func (r *Redactor) AddPasswordVars(vars any) {
+	switch typed := vars.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			if slices.Contains(passwordVarNames, key) {
+				r.addValue(value)
+
+				continue
+			}
+
+			r.AddPasswordVars(value)
+		}
+	case []any:
+		for _, element := range typed {
+			r.AddPasswordVars(element)
+		}
+	}
+}
+
+// AddInventoryPasswords registers the values of the password variables of a JSON inventory.
+// Invalid inventories are ignored, ansible-playbook reports them.
+func (r *Redactor) AddInventoryPasswords(inventory string) {
+	var decoded any
+
+	err := json.Unmarshal([]byte(inventory), &decoded)
+	if err == nil {
+		r.AddPasswordVars(decoded)
+	}
+}
+
+// AddSecretFile registers the content of a password file, e.g. a vault or a become password file.
+// Executable files are scripts printing the password, and are skipped. Files which can't be read
+// are ignored, the Ansible CLIs report them.
+func (r *Redactor) AddSecretFile(filePath string) {
+	if filePath == "" {
+		return
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&executablePerm != 0 {
+		return
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+
+	r.AddSecrets(strings.TrimSpace(string(content)))
+}
+
+// addValue registers the values of a password variable, ignoring the short ones (see minPasswordVarLength).
+func (r *Redactor) addValue(value any) {
+	switch typed := value.(type) {
+	case string:
+		r.addPasswordVar(typed)
+	case json.Number:
+		r.addPasswordVar(typed.String())
+	case map[string]any:
+		for _, element := range typed {
+			r.addValue(element)
+		}
+	case []any:
+		for _, element := range typed {
+			r.addValue(element)
+		}
+	}
+}
+
+func (r *Redactor) addPasswordVar(value string) {
+	if len(value) >= minPasswordVarLength {
+		r.AddSecrets(value)
+	}
+}
+
+// Redact masks the registered secrets in the given text.
+func (r *Redactor) Redact(text string) string {
+	if r == nil || len(r.secrets) == 0 {
+		return text
+	}
+
+	if r.replacer == nil {
+		// The longest secrets first, so that a secret containing another one is masked as a whole.
+		secrets := slices.Clone(r.secrets)
+		slices.SortFunc(secrets, func(a, b string) int { return len(b) - len(a) })
+
+		oldNew := make([]string, 0, 2*len(secrets))
+		for _, secret := range secrets {
+			oldNew = append(oldNew, secret, RedactedValue)
+		}
+
+		r.replacer = strings.NewReplacer(oldNew...)
+	}
+
+	return r.replacer.Replace(text)
+}
+
+// RedactArgs masks the registered secrets in each of the given command line arguments.
+func (r *Redactor) RedactArgs(args []string) []string {
+	redacted := make([]string, 0, len(args))
+	for _, arg := range args {
+		redacted = append(redacted, r.Redact(arg))
+	}
+
+	return redacted
+}
@@ -0,0 +1,204 @@
+package providerutils_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactorRedact(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		secrets []string
+		text    string
+		want    string
+	}{
+		{
+			name:    "no secrets",
+			secrets: nil,
+			text:    "ok: [host] password=hunter2",
+			want:    "ok: [host] password=hunter2",
+		},
+		{
+			name:    "every occurrence",
+			secrets: []string{"hunter2"},
+			text:    "hunter2 and hunter2",
+			want:    "(sensitive value) and (sensitive value)",
+		},
+		{
+			name:    "longest first when a secret is a prefix of another",
+			secrets: []string{"hunter", "hunter2"},
+			text:    "one=hunter2 two=hunter",
+			want:    "one=(sensitive value) two=(sensitive value)",
+		},
+		{
+			name:    "longest first regardless of the order of registration",
+			secrets: []string{"hunter2", "hunter"},
+			text:    "hunter2hunter",
+			want:    "(sensitive value)(sensitive value)",
+		},
+		{
+			name:    "empty values are ignored",
+			secrets: []string{""},
+			text:    "nothing to hide",
+			want:    "nothing to hide",
+		},
+		{
+			name:    "short secrets",
+			secrets: []string{"abc"},
+			text:    "password=abc",
+			want:    "password=(sensitive value)",
+		},
+		{
+			name:    "duplicates",
+			secrets: []string{"hunter2", "hunter2"},
+			text:    "hunter2",
+			want:    "(sensitive value)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			redactor := providerutils.NewRedactor()
+			redactor.AddSecrets(test.secrets...)
+
+			assert.Equal(t, test.want, redactor.Redact(test.text))
+		})
+	}
+}
+
+func TestRedactorNil(t *testing.T) {
+	t.Parallel()
+
+	var redactor *providerutils.Redactor
+
+	assert.Equal(t, "hunter2", redactor.Redact("hunter2"))
+}
+
+func TestRedactorAddSecretsAfterRedact(t *testing.T) {
+	t.Parallel()
+
+	redactor := providerutils.NewRedactor()
+	redactor.AddSecrets("first-secret")
+
+	assert.Equal(t, "(sensitive value) second-secret", redactor.Redact("first-secret second-secret"))
+
+	redactor.AddSecrets("second-secret")
+
+	assert.Equal(t, "(sensitive value) (sensitive value)", redactor.Redact("first-secret second-secret"))
+}
+
+func TestRedactorAddPasswordVars(t *testing.T) {
+	t.Parallel()
+
+	var vars any
+
+	err := json.Unmarshal([]byte(`{
+		"ansible_password": "connection-pw",
+		"ansible_user": "deploy",
+		"db_password": "not-a-connection-password",
+		"hosts": [
+			{"name": "web", "vars": {"ansible_become_password": "become-pw"}}
+		],
+		"groups": {
+			"db": {"ansible_ssh_pass": ["list-pw", {"nested": "nested-pw"}]}
+		}
+	}`), &vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redactor := providerutils.NewRedactor()
+	redactor.AddPasswordVars(vars)
+
+	assert.Equal(t,
+		"(sensitive value) deploy not-a-connection-password (sensitive value) (sensitive value) (sensitive value)",
+		redactor.Redact("connection-pw deploy not-a-connection-password become-pw list-pw nested-pw"),
+	)
+}
+
+func TestRedactorAddPasswordVarsNumbers(t *testing.T) {
+	t.Parallel()
+
+	redactor := providerutils.NewRedactor()
+	redactor.AddPasswordVars(map[string]any{"ansible_password": json.Number("12345678")})
+
+	assert.Equal(t, "pin=(sensitive value)", redactor.Redact("pin=12345678"))
+}
+
+func TestRedactorAddPasswordVarsShortValues(t *testing.T) {
+	t.Parallel()
+
+	redactor := providerutils.NewRedactor()
+	redactor.AddPasswordVars(map[string]any{
+		"ansible_password":        "yes",
+		"ansible_become_password": json.Number("1"),
+	})
+
+	assert.Equal(t, "changed=1 failed=0 yes", redactor.Redact("changed=1 failed=0 yes"))
+}
+
+func TestRedactorAddInventoryPasswords(t *testing.T) {
+	t.Parallel()
+
+	redactor := providerutils.NewRedactor()
+	redactor.AddInventoryPasswords(`{"all":{"hosts":{"web":{"ansible_become_pass":"inventory-pw"}}}}`)
+	redactor.AddInventoryPasswords(`not json`)
+
+	assert.Equal(t, "(sensitive value)", redactor.Redact("inventory-pw"))
+}
+
+func TestRedactorAddSecretFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("  file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	shortPasswordFile := filepath.Join(dir, "short-password")
+	if err := os.WriteFile(shortPasswordFile, []byte("abc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	script := filepath.Join(dir, "password-client")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho script-secret\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	redactor := providerutils.NewRedactor()
+	redactor.AddSecretFile(passwordFile)
+	redactor.AddSecretFile(shortPasswordFile)
+	redactor.AddSecretFile(script)
+	redactor.AddSecretFile(filepath.Join(dir, "missing"))
+	redactor.AddSecretFile(dir)
+	redactor.AddSecretFile("")
+
+	// The content of scripts is code, not the password, so it isn't masked.
+	assert.Equal(t,
+		"(sensitive value) (sensitive value) #!/bin/sh",
+		redactor.Redact("file-secret abc #!/bin/sh"),
+	)
+}
+
+func TestRedactorRedactArgs(t *testing.T) {
+	t.Parallel()
+
+	redactor := providerutils.NewRedactor()
+	redactor.AddSecrets("hunter2")
+
+	assert.Equal(t,
+		[]string{"-e", "password=(sensitive value)", "site.yml"},
+		redactor.RedactArgs([]string{"-e", "password=hunter2", "site.yml"}),
+	)
+}
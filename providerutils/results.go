@@ -0,0 +1,197 @@
+package providerutils
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resultsCallbackPlugin is an aggregate callback plugin shipped with the provider.
+// It does not replace the stdout callback, so the human readable output stays as it is.
+//
+//go:embed callback_plugins/terraform_results.py
+var resultsCallbackPlugin []byte
+
+const resultsFileEnv = "TF_ANSIBLE_RESULTS_FILE"
+
+// Task statuses reported by the results callback.
+const (
+	TaskStatusOk          = "ok"
+	TaskStatusChanged     = "changed"
+	TaskStatusFailed      = "failed"
+	TaskStatusIgnored     = "ignored"
+	TaskStatusSkipped     = "skipped"
+	TaskStatusUnreachable = "unreachable"
+)
+
+// HostRecap is the PLAY RECAP line of a single host.
+type HostRecap struct {
+	Host        string `json:"host"`
+	Ok          int64  `json:"ok"`
+	Changed     int64  `json:"changed"`
+	Unreachable int64  `json:"unreachable"`
+	Failed      int64  `json:"failed"`
+	Skipped     int64  `json:"skipped"`
+	Rescued     int64  `json:"rescued"`
+	Ignored     int64  `json:"ignored"`
+}
+
+// TaskResult is the outcome of a single task on a single host.
+type TaskResult struct {
+	Play    string `json:"play"`
+	Task    string `json:"task"`
+	Host    string `json:"host"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// runCustomStatsKey is where Ansible aggregates the custom stats which are not set per host.
+const runCustomStatsKey = "_run"
+
+// PlaybookResults holds the structured results of a playbook run.
+type PlaybookResults struct {
+	Recap []HostRecap  `json:"recap"`
+	Tasks []TaskResult `json:"tasks"`
+	// CustomStats holds the data published with `set_stats`, keyed by host,
+	// or by "_run" for data which is not set per host.
+	//
+	//nolint:tagliatelle // matches the callback plugin output
+	CustomStats map[string]map[string]any `json:"custom_stats"`
+}
+
+// Outputs returns the data published with `set_stats` (without `per_host`).
+// String values are kept as they are, other values are JSON encoded.
+func (r *PlaybookResults) Outputs() (map[string]string, error) {
+	outputs := map[string]string{}
+
+	for key, value := range r.CustomStats[runCustomStatsKey] {
+		if str, ok := value.(string); ok {
+			outputs[key] = str
+
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't encode output %q: %w", key, err)
+		}
+
+		outputs[key] = string(encoded)
+	}
+
+	return outputs, nil
+}
+
+// HostOutputs returns the data published with `set_stats` and `per_host: true`,
+// as a JSON object per host.
+func (r *PlaybookResults) HostOutputs() (map[string]string, error) {
+	outputs := map[string]string{}
+
+	for host, stats := range r.CustomStats {
+		if host == runCustomStatsKey {
+			continue
+		}
+
+		encoded, err := json.Marshal(stats)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't encode outputs of host %q: %w", host, err)
+		}
+
+		outputs[host] = string(encoded)
+	}
+
+	return outputs, nil
+}
+
+// Failures returns the failed and unreachable task results.
+func (r *PlaybookResults) Failures() []TaskResult {
+	failures := []TaskResult{}
+
+	for _, task := range r.Tasks {
+		if task.Status == TaskStatusFailed || task.Status == TaskStatusUnreachable {
+			failures = append(failures, task)
+		}
+	}
+
+	return failures
+}
+
+// ResultsCallback installs the results callback plugin for a single ansible-playbook run.
+type ResultsCallback struct {
+	dir string
+}
+
+// NewResultsCallback writes the callback plugin into a private temporary directory.
+// Call Cleanup once the results have been read.
+func NewResultsCallback() (*ResultsCallback, error) {
+	dir, err := os.MkdirTemp("", "terraform-provider-ansible-callback-*")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create callback plugin directory: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "terraform_results.py"), resultsCallbackPlugin, 0o600)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+
+		return nil, fmt.Errorf("couldn't write callback plugin: %w", err)
+	}
+
+	return &ResultsCallback{dir: dir}, nil
+}
+
+func (r *ResultsCallback) resultsFile() string {
+	return filepath.Join(r.dir, "results.json")
+}
+
+// Env returns the environment variables which enable the callback plugin,
+// to be appended to the ansible-playbook command environment.
+func (r *ResultsCallback) Env() []string {
+	callbackPlugins := r.dir
+	if existing := os.Getenv("ANSIBLE_CALLBACK_PLUGINS"); existing != "" {
+		callbackPlugins = strings.Join([]string{r.dir, existing}, string(os.PathListSeparator))
+	}
+
+	return []string{
+		"ANSIBLE_CALLBACK_PLUGINS=" + callbackPlugins,
+		resultsFileEnv + "=" + r.resultsFile(),
+	}
+}
+
+// Results reads the results written by the callback plugin.
+// Empty results are returned if the playbook stopped before reaching the recap (e.g. a syntax error).
+func (r *ResultsCallback) Results() (*PlaybookResults, error) {
+	results := &PlaybookResults{
+		Recap: []HostRecap{},
+		Tasks: []TaskResult{},
+	}
+
+	content, err := os.ReadFile(r.resultsFile())
+	if errors.Is(err, os.ErrNotExist) {
+		return results, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read playbook results: %w", err)
+	}
+
+	err = json.Unmarshal(content, results)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse playbook results: %w", err)
+	}
+
+	return results, nil
+}
+
+// Cleanup removes the callback plugin and its results.
+func (r *ResultsCallback) Cleanup() error {
+	err := os.RemoveAll(r.dir)
+	if err != nil {
+		return fmt.Errorf("couldn't remove callback plugin directory: %w", err)
+	}
+
+	return nil
+}
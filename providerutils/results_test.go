@@ -0,0 +1,132 @@
+package providerutils_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A results file as written by callback_plugins/terraform_results.py.
+const resultsJSON = `{
+	"recap": [
+		{"host": "db-1", "ok": 3, "changed": 0, "unreachable": 1, "failed": 0, "skipped": 0, "rescued": 0, "ignored": 0},
+		{"host": "web-1", "ok": 4, "changed": 2, "unreachable": 0, "failed": 1, "skipped": 1, "rescued": 1, "ignored": 1}
+	],
+	"tasks": [
+		{"play": "Deploy", "task": "Gathering Facts", "host": "web-1", "status": "ok", "message": ""},
+		{"play": "Deploy", "task": "Install nginx", "host": "web-1", "status": "changed", "message": ""},
+		{"play": "Deploy", "task": "Check config", "host": "web-1", "status": "failed", "message": "nginx -t failed"},
+		{"play": "Deploy", "task": "Optional", "host": "web-1", "status": "ignored", "message": "not found"},
+		{"play": "Deploy", "task": "Gathering Facts", "host": "db-1", "status": "unreachable", "message": "timed out"}
+	],
+	"custom_stats": {
+		"_run": {"version": "1.2.3", "ports": [80, 443], "tls": true},
+		"web-1": {"url": "http://web-1"}
+	}
+}`
+
+// resultsFile returns the path the callback plugin writes the results to.
+func resultsFile(t *testing.T, callback *providerutils.ResultsCallback) string {
+	t.Helper()
+
+	for _, env := range callback.Env() {
+		path, found := strings.CutPrefix(env, "TF_ANSIBLE_RESULTS_FILE=")
+		if found {
+			return path
+		}
+	}
+
+	require.FailNow(t, "the results file isn't set")
+
+	return ""
+}
+
+func newResultsCallback(t *testing.T) *providerutils.ResultsCallback {
+	t.Helper()
+
+	callback, err := providerutils.NewResultsCallback()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, callback.Cleanup())
+	})
+
+	return callback
+}
+
+func TestResultsCallback(t *testing.T) {
+	t.Parallel()
+
+	callback := newResultsCallback(t)
+
+	path := resultsFile(t, callback)
+	require.NoError(t, os.WriteFile(path, []byte(resultsJSON), 0o600))
+
+	// The plugin is installed next to the results file.
+	assert.True(t, slices.ContainsFunc(callback.Env(), func(env string) bool {
+		return strings.HasPrefix(env, "ANSIBLE_CALLBACK_PLUGINS="+filepath.Dir(path))
+	}), callback.Env())
+	assert.FileExists(t, filepath.Join(filepath.Dir(path), "terraform_results.py"))
+
+	results, err := callback.Results()
+	require.NoError(t, err)
+
+	assert.Equal(t, []providerutils.HostRecap{
+		{Host: "db-1", Ok: 3, Unreachable: 1},
+		{Host: "web-1", Ok: 4, Changed: 2, Failed: 1, Skipped: 1, Rescued: 1, Ignored: 1},
+	}, results.Recap)
+	assert.Len(t, results.Tasks, 5)
+
+	assert.Equal(t, []providerutils.TaskResult{
+		{Play: "Deploy", Task: "Check config", Host: "web-1", Status: providerutils.TaskStatusFailed, Message: "nginx -t failed"},
+		{Play: "Deploy", Task: "Gathering Facts", Host: "db-1", Status: providerutils.TaskStatusUnreachable, Message: "timed out"},
+	}, results.Failures())
+
+	outputs, err := results.Outputs()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"version": "1.2.3", "ports": "[80,443]", "tls": "true"}, outputs)
+
+	hostOutputs, err := results.HostOutputs()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"web-1": `{"url":"http://web-1"}`}, hostOutputs)
+
+	require.NoError(t, callback.Cleanup())
+	assert.NoDirExists(t, filepath.Dir(path))
+}
+
+// The callback plugin writes no results when the playbook stops before the recap, e.g. on a syntax error.
+func TestResultsCallbackNoResults(t *testing.T) {
+	t.Parallel()
+
+	results, err := newResultsCallback(t).Results()
+	require.NoError(t, err)
+
+	assert.Empty(t, results.Recap)
+	assert.Empty(t, results.Tasks)
+	assert.Empty(t, results.Failures())
+
+	outputs, err := results.Outputs()
+	require.NoError(t, err)
+	assert.Empty(t, outputs)
+
+	hostOutputs, err := results.HostOutputs()
+	require.NoError(t, err)
+	assert.Empty(t, hostOutputs)
+}
+
+func TestResultsCallbackInvalidResults(t *testing.T) {
+	t.Parallel()
+
+	callback := newResultsCallback(t)
+
+	require.NoError(t, os.WriteFile(resultsFile(t, callback), []byte(`{"recap": [`), 0o600))
+
+	_, err := callback.Results()
+	require.Error(t, err)
+}
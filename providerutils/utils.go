@@ -1,15 +1,14 @@
 package providerutils
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"gopkg.in/ini.v1"
 )
 
 /*
@@ -53,84 +52,20 @@ func CreateVerboseSwitch(verbosity int) string {
 	return verbose
 }
 
-// Build inventory.ini (NOT YAML)
-//  -- building inventory.ini is easier
-
-func BuildPlaybookInventory(
-	inventoryDest string,
-	hostname string,
-	port int,
-	hostgroups []any,
-) (string, diag.Diagnostics) {
-	var diags diag.Diagnostics
-	// Check if inventory file is already present
-	// if not, create one
-	fileInfo, err := os.CreateTemp("", inventoryDest)
-	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Fail to create inventory file: %v", err),
-		})
-	}
-
-	tempFileName := fileInfo.Name()
-	log.Printf("Inventory %s was created", fileInfo.Name())
-
-	// Then, read inventory and add desired settings to it
-	inventory, err := ini.Load(tempFileName)
-	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Fail to read inventory: %v", err),
-		})
-	}
-
-	tempHostgroups := hostgroups
-
-	if len(tempHostgroups) == 0 {
-		tempHostgroups = append(tempHostgroups, DefaultHostGroup)
+// ExtraVarsFile writes the extra vars into a private temporary JSON file, to be passed to ansible-playbook
+// as "-e @file" and removed after the run. Values keep their types, aren't quoted on the command line,
+// and don't show up in the arguments or the logs. No file is written for empty extra vars, and "" is returned.
+func ExtraVarsFile(extraVars map[string]any) (string, error) {
+	if len(extraVars) == 0 {
+		return "", nil
 	}
 
-	if len(tempHostgroups) > 0 { // if there is a list of groups specified for the desired host
-		for _, hostgroup := range tempHostgroups {
-			hostgroupStr, okay := hostgroup.(string)
-			if !okay {
-				diags = append(diags, diag.Diagnostic{
-					Severity: diag.Error,
-					Summary:  "Couldn't assert type: string",
-				})
-			}
-
-			if !inventory.HasSection(hostgroupStr) {
-				_, err := inventory.NewRawSection(hostgroupStr, "")
-				if err != nil {
-					diags = append(diags, diag.Diagnostic{
-						Severity: diag.Error,
-						Summary:  fmt.Sprintf("Fail to create a hostgroup: %v", err),
-					})
-				}
-			}
-
-			if !inventory.Section(hostgroupStr).HasKey(hostname) {
-				body := hostname
-				if port != -1 {
-					body += " ansible_port=" + strconv.Itoa(port)
-				}
-
-				inventory.Section(hostgroupStr).SetBody(body)
-			}
-		}
-	}
-
-	err = inventory.SaveTo(tempFileName)
+	payload, err := json.Marshal(extraVars)
 	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Fail to create inventory: %v", err),
-		})
+		return "", fmt.Errorf("couldn't encode extra vars: %w", err)
 	}
 
-	return tempFileName, diags
+	return WriteTempFile(".extra-vars-*.json", payload)
 }
 
 func RemoveFile(filename string) diag.Diagnostics {
@@ -0,0 +1,305 @@
+package providerutils
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The Ansible Vault format, see
+// https://docs.ansible.com/ansible/latest/vault_guide/vault_using_encrypted_content.html#ansible-vault-payload-format-1-1-1-2
+// The header is '$ANSIBLE_VAULT;1.1;AES256', or '$ANSIBLE_VAULT;1.2;AES256;<vault id>', followed by the hex encoding
+// of the hex encoded salt, HMAC and ciphertext, one per line. The AES key, the HMAC key and the CTR IV are derived
+// from the password and the salt with PBKDF2-SHA256.
+const (
+	vaultMagic      = "$ANSIBLE_VAULT"
+	vaultCipher     = "AES256"
+	vaultKeySize    = 32
+	vaultSaltSize   = 32
+	vaultIterations = 10000
+	vaultLineWidth  = 80
+)
+
+// defaultVaultID is the vault ID of vaults encrypted without one, in the 1.1 format.
+const defaultVaultID = "default"
+
+// vaultDirPermission is the permission of the directories created for vault files.
+const vaultDirPermission = 0o700
+
+var (
+	errNotVault         = errors.New("not an Ansible Vault, the '$ANSIBLE_VAULT' header is missing")
+	errUnsupportedVault = errors.New("unsupported Ansible Vault")
+	errInvalidVault     = errors.New("invalid Ansible Vault")
+	errVaultDecryption  = errors.New("decryption failed, the vault password is wrong or the vault is corrupted")
+	errEmptyPassword    = errors.New("the vault password is empty")
+)
+
+// VaultHeader is the first line of an Ansible Vault.
+type VaultHeader struct {
+	Version string
+	Cipher  string
+	VaultID string
+}
+
+// parseVault splits a vault into its header and its decoded payload: salt, HMAC and ciphertext.
+func parseVault(content []byte) (VaultHeader, [3][]byte, error) {
+	var payload [3][]byte
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+
+	fields := strings.Split(strings.TrimSpace(lines[0]), ";")
+	if fields[0] != vaultMagic || len(fields) < 3 {
+		return VaultHeader{}, payload, errNotVault
+	}
+
+	header := VaultHeader{Version: fields[1], Cipher: strings.TrimSpace(fields[2])}
+	if len(fields) > 3 {
+		header.VaultID = strings.TrimSpace(fields[3])
+	}
+
+	if header.Version != "1.1" && header.Version != "1.2" {
+		return header, payload, fmt.Errorf("%w version %q, expected 1.1 or 1.2", errUnsupportedVault, header.Version)
+	}
+
+	if header.Cipher != vaultCipher {
+		return header, payload, fmt.Errorf("%w cipher %q, expected %s", errUnsupportedVault, header.Cipher, vaultCipher)
+	}
+
+	var encoded strings.Builder
+	for _, line := range lines[1:] {
+		encoded.WriteString(strings.TrimSpace(line))
+	}
+
+	decoded, err := hex.DecodeString(encoded.String())
+	if err != nil {
+		return header, payload, fmt.Errorf("%w: %w", errInvalidVault, err)
+	}
+
+	parts := bytes.SplitN(decoded, []byte("\n"), len(payload))
+	if len(parts) != len(payload) {
+		return header, payload, fmt.Errorf("%w: expected a salt, an HMAC and a ciphertext", errInvalidVault)
+	}
+
+	for index, part := range parts {
+		payload[index], err = hex.DecodeString(string(part))
+		if err != nil {
+			return header, payload, fmt.Errorf("%w: %w", errInvalidVault, err)
+		}
+	}
+
+	return header, payload, nil
+}
+
+// vaultKeys derives the AES key, the HMAC key and the CTR IV from a password and a salt.
+func vaultKeys(password []byte, salt []byte) ([]byte, []byte, []byte, error) {
+	derived, err := pbkdf2.Key(sha256.New, string(password), salt, vaultIterations, 2*vaultKeySize+aes.BlockSize)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("couldn't derive the vault keys: %w", err)
+	}
+
+	return derived[:vaultKeySize], derived[vaultKeySize : 2*vaultKeySize], derived[2*vaultKeySize:], nil
+}
+
+// DecryptVault decrypts an Ansible Vault (format 1.1 or 1.2, AES256) with the given password,
+// like 'ansible-vault view' does, without Ansible.
+func DecryptVault(content []byte, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, errEmptyPassword
+	}
+
+	_, payload, err := parseVault(content)
+	if err != nil {
+		return nil, err
+	}
+
+	salt, expectedMAC, ciphertext := payload[0], payload[1], payload[2]
+
+	aesKey, hmacKey, iv, err := vaultKeys(password, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(ciphertext)
+
+	if !hmac.Equal(mac.Sum(nil), expectedMAC) {
+		return nil, errVaultDecryption
+	}
+
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errVaultDecryption, err)
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCTR(block, iv).XORKeyStream(plaintext, ciphertext)
+
+	// The plaintext is padded with PKCS#7: 1 to 16 bytes, each one holding the length of the padding.
+	if len(plaintext) == 0 {
+		return nil, fmt.Errorf("%w: invalid padding", errInvalidVault)
+	}
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(plaintext) ||
+		!bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, fmt.Errorf("%w: invalid padding", errInvalidVault)
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// EncryptVault encrypts content with the given password like 'ansible-vault encrypt' does: in the 1.2 format
+// with the vault ID in the header, or in the 1.1 format without a vault ID (or with the "default" one).
+// Every encryption uses a new random salt, so that encrypting the same content twice gives different vaults.
+func EncryptVault(plaintext []byte, password []byte, vaultID string) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, errEmptyPassword
+	}
+
+	salt := make([]byte, vaultSaltSize)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate the vault salt: %w", err)
+	}
+
+	aesKey, hmacKey, iv, err := vaultKeys(password, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't encrypt the vault: %w", err)
+	}
+
+	// The plaintext is padded with PKCS#7.
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(slices.Clone(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCTR(block, iv).XORKeyStream(ciphertext, padded)
+
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(ciphertext)
+
+	payload := hex.EncodeToString(bytes.Join([][]byte{
+		[]byte(hex.EncodeToString(salt)),
+		[]byte(hex.EncodeToString(mac.Sum(nil))),
+		[]byte(hex.EncodeToString(ciphertext)),
+	}, []byte("\n")))
+
+	var vault strings.Builder
+
+	vault.WriteString(vaultMagic + ";1.1;" + vaultCipher)
+
+	if vaultID != "" && vaultID != defaultVaultID {
+		vault.Reset()
+		vault.WriteString(vaultMagic + ";1.2;" + vaultCipher + ";" + vaultID)
+	}
+
+	vault.WriteString("\n")
+
+	for start := 0; start < len(payload); start += vaultLineWidth {
+		vault.WriteString(payload[start:min(start+vaultLineWidth, len(payload))])
+		vault.WriteString("\n")
+	}
+
+	return []byte(vault.String()), nil
+}
+
+// WriteVaultFile writes an encrypted vault with the given permission, creating its directory if needed.
+// The vault is written to a temporary file which is renamed, so that the vault file is never partially written.
+func WriteVaultFile(vaultFile string, content []byte, permission os.FileMode) error {
+	dir := filepath.Dir(vaultFile)
+
+	err := os.MkdirAll(dir, vaultDirPermission)
+	if err != nil {
+		return fmt.Errorf("couldn't create the vault directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, "."+filepath.Base(vaultFile)+".*")
+	if err != nil {
+		return fmt.Errorf("couldn't write the vault file: %w", err)
+	}
+
+	_, err = file.Write(content)
+	if err == nil {
+		err = file.Chmod(permission)
+	}
+
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), vaultFile)
+	}
+
+	if err != nil {
+		_ = os.Remove(file.Name())
+
+		return fmt.Errorf("couldn't write the vault file: %w", err)
+	}
+
+	return nil
+}
+
+// vaultClientScript matches the vault password client scripts, which are given the vault ID to look up.
+var vaultClientScript = regexp.MustCompile(`-client(\.[^.]*)?$`)
+
+// ReadVaultPasswordFile reads a vault password file like Ansible does: the content of the file, or the output
+// of the file if it is an executable script, without surrounding whitespace. Client scripts, whose name ends
+// with '-client', are given the vault ID with '--vault-id'.
+func ReadVaultPasswordFile(ctx context.Context, passwordFile string, vaultID string) ([]byte, error) {
+	info, err := os.Stat(passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the vault password file: %w", err)
+	}
+
+	var password []byte
+
+	if info.Mode().IsRegular() && info.Mode().Perm()&executablePerm != 0 {
+		args := []string{}
+		if vaultClientScript.MatchString(filepath.Base(passwordFile)) {
+			args = append(args, "--vault-id", orDefault(vaultID, "default"))
+		}
+
+		var stderr bytes.Buffer
+
+		cmd := exec.CommandContext(ctx, passwordFile, args...)
+		cmd.Stderr = &stderr
+
+		password, err = cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("vault password script %s failed: %w: %s", passwordFile, err, stderr.String())
+		}
+	} else {
+		password, err = os.ReadFile(passwordFile)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read the vault password file: %w", err)
+		}
+	}
+
+	password = bytes.TrimSpace(password)
+	if len(password) == 0 {
+		return nil, fmt.Errorf("%w: %s", errEmptyPassword, passwordFile)
+	}
+
+	return password, nil
+}
@@ -0,0 +1,298 @@
+package providerutils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of the decrypted content of a vault.
+const (
+	VaultFormatYAML = "yaml"
+	VaultFormatJSON = "json"
+	VaultFormatEnv  = "env"
+	VaultFormatText = "text"
+)
+
+// VaultFormats are the supported formats of the decrypted content of a vault.
+var VaultFormats = []string{VaultFormatYAML, VaultFormatJSON, VaultFormatEnv, VaultFormatText}
+
+var (
+	errUnknownVaultFormat = errors.New("unknown vault content format")
+	errInvalidVaultFormat = errors.New("invalid vault content")
+	errTrailingContent    = errors.New("unexpected content after the closing quote")
+)
+
+// envVariable matches the variable names of env files.
+var envVariable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// DecodeVaultContent decodes the decrypted content of a vault in the given format. It returns the top-level
+// keys of the content, where values which are not strings are JSON encoded, and the whole content as JSON.
+//
+// YAML content can hold several documents: the JSON is then a list of the documents, and the keys of the
+// documents are merged, in order. Text content is encoded as a JSON string, and has no keys.
+func DecodeVaultContent(content []byte, format string) (map[string]string, string, error) {
+	var (
+		decoded any
+		err     error
+	)
+
+	switch format {
+	case VaultFormatYAML:
+		decoded, err = decodeYAMLDocuments(content)
+	case VaultFormatJSON:
+		decoded, err = decodeJSONDocument(content)
+	case VaultFormatEnv:
+		decoded, err = decodeEnv(content)
+	case VaultFormatText:
+		decoded = string(content)
+	default:
+		err = fmt.Errorf("%w %q, expected one of %s", errUnknownVaultFormat, format, strings.Join(VaultFormats, ", "))
+	}
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	encoded, err := canonicalJSON(decoded)
+	if err != nil {
+		return nil, "", err
+	}
+
+	keys := map[string]any{}
+
+	switch typed := decoded.(type) {
+	case map[string]any:
+		keys = typed
+	case []any:
+		if format == VaultFormatYAML {
+			for _, document := range typed {
+				if document, ok := document.(map[string]any); ok {
+					maps.Copy(keys, document)
+				}
+			}
+		}
+	}
+
+	data, err := stringValues(keys)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, string(encoded), nil
+}
+
+// VaultYAML renders variables as the YAML content of a vault, with sorted keys, so that the output is stable.
+func VaultYAML(vars map[string]any) (string, error) {
+	var buffer bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(yamlIndent)
+
+	err := encoder.Encode(yamlValue(vars))
+	if err != nil {
+		return "", fmt.Errorf("couldn't encode the vault content to YAML: %w", err)
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return "", fmt.Errorf("couldn't encode the vault content to YAML: %w", err)
+	}
+
+	return buffer.String(), nil
+}
+
+// stringValues converts decoded variables into a map of strings, where values which are not strings
+// are JSON encoded.
+func stringValues(values map[string]any) (map[string]string, error) {
+	stringValues := make(map[string]string, len(values))
+
+	for key, value := range values {
+		if str, ok := value.(string); ok {
+			stringValues[key] = str
+
+			continue
+		}
+
+		encoded, err := canonicalJSON(value)
+		if err != nil {
+			return nil, fmt.Errorf("variable %q: %w", key, err)
+		}
+
+		stringValues[key] = string(encoded)
+	}
+
+	return stringValues, nil
+}
+
+// decodeYAMLDocuments decodes every document of a YAML stream, skipping empty ones: the only document,
+// a list of the documents if there are several, or nil if there are none.
+func decodeYAMLDocuments(content []byte) (any, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+
+	documents := []any{}
+
+	for {
+		var node yaml.Node
+
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err == nil {
+			keepTimestamps(&node)
+		}
+
+		var document any
+		if err == nil {
+			err = node.Decode(&document)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidVaultFormat, err)
+		}
+
+		if document != nil {
+			documents = append(documents, stringKeys(document))
+		}
+	}
+
+	switch len(documents) {
+	case 0:
+		return nil, nil
+	case 1:
+		return documents[0], nil
+	default:
+		return documents, nil
+	}
+}
+
+// keepTimestamps decodes timestamps as the strings they are written as, like yamldecode does,
+// instead of reformatting them.
+func keepTimestamps(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!timestamp" {
+		node.Tag = "!!str"
+	}
+
+	for _, child := range node.Content {
+		keepTimestamps(child)
+	}
+}
+
+// decodeJSONDocument decodes a JSON document, keeping its numbers as they are.
+func decodeJSONDocument(content []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var decoded any
+
+	err := decoder.Decode(&decoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidVaultFormat, err)
+	}
+
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: unexpected content after the JSON document", errInvalidVaultFormat)
+	}
+
+	return decoded, nil
+}
+
+// decodeEnv decodes env files, like the ones of docker compose or systemd: 'NAME=value' lines, optionally
+// prefixed with 'export', with comments starting with '#'. Values can be single quoted, taken as is,
+// or double quoted, with backslash escapes.
+//
+// Errors only give the line number, the content is secret.
+func decodeEnv(content []byte) (map[string]any, error) {
+	variables := map[string]any{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+
+		if !ok || !envVariable.MatchString(name) {
+			return nil, fmt.Errorf("%w: line %d is not a NAME=value assignment", errInvalidVaultFormat, number)
+		}
+
+		value, err := envValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", errInvalidVaultFormat, number, err)
+		}
+
+		variables[name] = value
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidVaultFormat, err)
+	}
+
+	return variables, nil
+}
+
+func envValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	quote := value[0]
+	if quote != '\'' && quote != '"' {
+		// Unquoted values end at a comment.
+		if index := strings.Index(value, " #"); index >= 0 {
+			value = value[:index]
+		}
+
+		return strings.TrimSpace(value), nil
+	}
+
+	var unquoted strings.Builder
+
+	for index := 1; index < len(value); index++ {
+		char := value[index]
+
+		switch {
+		case char == quote:
+			rest := strings.TrimSpace(value[index+1:])
+			if rest != "" && !strings.HasPrefix(rest, "#") {
+				return "", errTrailingContent
+			}
+
+			return unquoted.String(), nil
+		case char == '\\' && quote == '"' && index+1 < len(value):
+			index++
+
+			switch value[index] {
+			case 'n':
+				unquoted.WriteByte('\n')
+			case 't':
+				unquoted.WriteByte('\t')
+			case 'r':
+				unquoted.WriteByte('\r')
+			default:
+				unquoted.WriteByte(value[index])
+			}
+		default:
+			unquoted.WriteByte(char)
+		}
+	}
+
+	return "", errUnterminatedQuote
+}
@@ -0,0 +1,158 @@
+package providerutils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Placeholders of the vault passwords which aren't read from a file, in the rendered arguments:
+// the password file is only created to run the Ansible CLIs.
+const (
+	VaultPasswordPlaceholder        = "(vault_password)"
+	VaultPasswordCommandPlaceholder = "(vault_password_command)"
+)
+
+var (
+	errNoVaultPassword      = errors.New("no vault password, set vault_password_file, vault_password or vault_password_command")
+	errEmptyPasswordCommand = errors.New("vault_password_command is empty")
+)
+
+// VaultPassword is the source of a vault password: a password file (or script), the password itself,
+// or a command printing the password. The password takes precedence over the command, and the command
+// over the file.
+type VaultPassword struct {
+	File     string
+	Password string
+	Command  []string
+}
+
+// IsSet reports whether the password has a source.
+func (v VaultPassword) IsSet() bool {
+	return v.Password != "" || len(v.Command) > 0 || v.File != ""
+}
+
+// Placeholder is the password file in the rendered arguments: the file, or a placeholder if the password
+// isn't read from a file.
+func (v VaultPassword) Placeholder() string {
+	switch {
+	case v.Password != "":
+		return VaultPasswordPlaceholder
+	case len(v.Command) > 0:
+		return VaultPasswordCommandPlaceholder
+	default:
+		return v.File
+	}
+}
+
+// Read returns the password, without surrounding whitespace, like Ansible reads password files.
+// The vault ID is given to password client scripts, see ReadVaultPasswordFile.
+func (v VaultPassword) Read(ctx context.Context, vaultID string) ([]byte, error) {
+	var password []byte
+
+	switch {
+	case v.Password != "":
+		password = []byte(v.Password)
+	case len(v.Command) > 0:
+		if v.Command[0] == "" {
+			return nil, errEmptyPasswordCommand
+		}
+
+		var stderr bytes.Buffer
+
+		cmd := exec.CommandContext(ctx, v.Command[0], v.Command[1:]...)
+		cmd.Stderr = &stderr
+
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("vault password command %s failed: %w: %s", v.Command[0], err, stderr.String())
+		}
+
+		password = output
+	case v.File != "":
+		return ReadVaultPasswordFile(ctx, v.File, vaultID)
+	default:
+		return nil, errNoVaultPassword
+	}
+
+	password = bytes.TrimSpace(password)
+	if len(password) == 0 {
+		return nil, errEmptyPassword
+	}
+
+	return password, nil
+}
+
+// PasswordFile returns a password file for the Ansible CLIs: the password file, or a temporary file holding
+// the password, only readable by the current user (0600), which must be removed with the returned function.
+func (v VaultPassword) PasswordFile(ctx context.Context, vaultID string) (string, func() error, error) {
+	if v.Password == "" && len(v.Command) == 0 {
+		if v.File == "" {
+			return "", nil, errNoVaultPassword
+		}
+
+		return v.File, func() error { return nil }, nil
+	}
+
+	password, err := v.Read(ctx, vaultID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Temporary files are created with the 0600 permissions.
+	passwordFile, err := WriteTempFile(".vault-password-*", append(password, '\n'))
+	if err != nil {
+		return "", nil, fmt.Errorf("couldn't write the vault password file: %w", err)
+	}
+
+	return passwordFile, func() error { return os.Remove(passwordFile) }, nil
+}
+
+// IsReferenced reports whether rendered arguments use the placeholder of a password which isn't read from a file.
+func (v VaultPassword) IsReferenced(args []string) bool {
+	placeholder := v.Placeholder()
+	if placeholder == v.File {
+		return false
+	}
+
+	return slices.ContainsFunc(args, func(arg string) bool {
+		return arg == placeholder || strings.HasSuffix(arg, "@"+placeholder)
+	})
+}
+
+// ReplacePlaceholder replaces the password placeholder of rendered arguments with the password file.
+func (v VaultPassword) ReplacePlaceholder(args []string, passwordFile string) []string {
+	placeholder := v.Placeholder()
+	if placeholder == passwordFile {
+		return args
+	}
+
+	replaced := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if strings.HasSuffix(arg, "@"+placeholder) {
+			arg = strings.TrimSuffix(arg, placeholder) + passwordFile
+		} else if arg == placeholder {
+			arg = passwordFile
+		}
+
+		replaced = append(replaced, arg)
+	}
+
+	return replaced
+}
+
+// DecryptVaultWithPassword decrypts a vault with a password from any source.
+func DecryptVaultWithPassword(ctx context.Context, content []byte, password VaultPassword, vaultID string) ([]byte, error) {
+	passwordBytes, err := password.Read(ctx, vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the vault password: %w", err)
+	}
+
+	return DecryptVault(content, passwordBytes)
+}
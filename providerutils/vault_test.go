@@ -0,0 +1,256 @@
+package providerutils_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ansible/terraform-provider-ansible/providerutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readVaultTestData(t *testing.T, name string) []byte {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "vault", name))
+	require.NoError(t, err)
+
+	return content
+}
+
+// sealVault encrypts an already padded plaintext in the 1.1 format, independently of EncryptVault,
+// so that vaults with an invalid padding but a valid HMAC can be built.
+func sealVault(t *testing.T, padded []byte, password string) []byte {
+	t.Helper()
+
+	salt := bytes.Repeat([]byte{0x42}, 32)
+
+	derived, err := pbkdf2.Key(sha256.New, password, salt, 10000, 80)
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(derived[:32])
+	require.NoError(t, err)
+
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCTR(block, derived[64:]).XORKeyStream(ciphertext, padded)
+
+	mac := hmac.New(sha256.New, derived[32:64])
+	mac.Write(ciphertext)
+
+	payload := hex.EncodeToString([]byte(strings.Join([]string{
+		hex.EncodeToString(salt), hex.EncodeToString(mac.Sum(nil)), hex.EncodeToString(ciphertext),
+	}, "\n")))
+
+	return []byte("$ANSIBLE_VAULT;1.1;AES256\n" + payload + "\n")
+}
+
+func TestDecryptVaultKnownAnswers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		file      string
+		password  string
+		header    providerutils.VaultHeader
+		plaintext string
+	}{
+		{
+			// Encrypted by ansible-vault, it is the vault of the ansible_playbook example.
+			name:      "1.1",
+			file:      "vault_1.1.yml",
+			password:  "password",
+			header:    providerutils.VaultHeader{Version: "1.1", Cipher: "AES256"},
+			plaintext: "content_from_a_vault_file: \"content from a vault file\"\n",
+		},
+		{
+			// Encrypted with the vault ID 'production' by a reference implementation of the format
+			// using Python's hashlib and hmac modules and 'openssl enc -aes-256-ctr', not by this package.
+			name:      "1.2 with a vault ID",
+			file:      "vault_1.2.yml",
+			password:  "vault-id-password",
+			header:    providerutils.VaultHeader{Version: "1.2", Cipher: "AES256", VaultID: "production"},
+			plaintext: "database:\n  user: admin\n  password: \"s3cr3t!\"\nports: [80, 443]\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			vault := readVaultTestData(t, test.file)
+
+			header, _, err := providerutils.ParseVault(vault)
+			require.NoError(t, err)
+			assert.Equal(t, test.header, header)
+
+			plaintext, err := providerutils.DecryptVault(vault, []byte(test.password))
+			require.NoError(t, err)
+			assert.Equal(t, test.plaintext, string(plaintext))
+
+			_, err = providerutils.DecryptVault(vault, []byte(test.password+"x"))
+			require.ErrorIs(t, err, providerutils.ErrVaultDecryption)
+		})
+	}
+}
+
+func TestEncryptVaultRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		plaintext string
+		vaultID   string
+		header    string
+	}{
+		{name: "empty", plaintext: "", header: "$ANSIBLE_VAULT;1.1;AES256"},
+		{name: "short", plaintext: "a", header: "$ANSIBLE_VAULT;1.1;AES256"},
+		{name: "one block minus one", plaintext: strings.Repeat("b", 15), header: "$ANSIBLE_VAULT;1.1;AES256"},
+		{name: "one block", plaintext: strings.Repeat("c", 16), header: "$ANSIBLE_VAULT;1.1;AES256"},
+		{name: "one block plus one", plaintext: strings.Repeat("d", 17), header: "$ANSIBLE_VAULT;1.1;AES256"},
+		{name: "unicode", plaintext: "clé: \"välue ✓\"\n", header: "$ANSIBLE_VAULT;1.1;AES256"},
+		{name: "default vault ID", plaintext: "key: value\n", vaultID: "default", header: "$ANSIBLE_VAULT;1.1;AES256"},
+		{name: "vault ID", plaintext: "key: value\n", vaultID: "prod", header: "$ANSIBLE_VAULT;1.2;AES256;prod"},
+		{name: "long", plaintext: strings.Repeat("line of the vault\n", 100), vaultID: "prod", header: "$ANSIBLE_VAULT;1.2;AES256;prod"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			vault, err := providerutils.EncryptVault([]byte(test.plaintext), []byte("hunter2"), test.vaultID)
+			require.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSuffix(string(vault), "\n"), "\n")
+			assert.Equal(t, test.header, lines[0])
+
+			for _, line := range lines[1:] {
+				assert.LessOrEqual(t, len(line), 80)
+			}
+
+			plaintext, err := providerutils.DecryptVault(vault, []byte("hunter2"))
+			require.NoError(t, err)
+			assert.Equal(t, test.plaintext, string(plaintext))
+
+			again, err := providerutils.EncryptVault([]byte(test.plaintext), []byte("hunter2"), test.vaultID)
+			require.NoError(t, err)
+			assert.NotEqual(t, string(vault), string(again), "the salt must be random")
+		})
+	}
+}
+
+func TestEncryptVaultEmptyPassword(t *testing.T) {
+	t.Parallel()
+
+	_, err := providerutils.EncryptVault([]byte("key: value"), nil, "")
+	require.ErrorIs(t, err, providerutils.ErrEmptyPassword)
+}
+
+func TestDecryptVaultInvalid(t *testing.T) {
+	t.Parallel()
+
+	vault := string(readVaultTestData(t, "vault_1.1.yml"))
+	lines := strings.Split(strings.TrimSuffix(vault, "\n"), "\n")
+
+	tampered := []byte(vault)
+	tampered[len(tampered)-2] ^= 0x01
+
+	tests := []struct {
+		name     string
+		vault    []byte
+		password string
+		err      error
+	}{
+		{name: "wrong password", vault: []byte(vault), password: "wrong", err: providerutils.ErrVaultDecryption},
+		{name: "empty password", vault: []byte(vault), password: "", err: providerutils.ErrEmptyPassword},
+		{name: "tampered ciphertext", vault: tampered, password: "password", err: providerutils.ErrVaultDecryption},
+		{name: "no header", vault: []byte(strings.Join(lines[1:], "\n")), password: "password", err: providerutils.ErrNotVault},
+		{name: "plaintext", vault: []byte("key: value\n"), password: "password", err: providerutils.ErrNotVault},
+		{
+			name:     "unsupported version",
+			vault:    []byte(strings.Replace(vault, ";1.1;", ";1.0;", 1)),
+			password: "password",
+			err:      providerutils.ErrUnsupportedVault,
+		},
+		{
+			name:     "unsupported cipher",
+			vault:    []byte(strings.Replace(vault, ";AES256", ";AES", 1)),
+			password: "password",
+			err:      providerutils.ErrUnsupportedVault,
+		},
+		{
+			name:     "truncated payload",
+			vault:    []byte(strings.Join(lines[:3], "\n")),
+			password: "password",
+			err:      providerutils.ErrInvalidVault,
+		},
+		{
+			name:     "truncated ciphertext",
+			vault:    []byte(strings.TrimSuffix(vault, "\n")[:len(vault)-3]),
+			password: "password",
+			err:      providerutils.ErrInvalidVault,
+		},
+		{
+			name:     "odd hex",
+			vault:    []byte(strings.TrimSuffix(vault, "\n")[:len(vault)-2]),
+			password: "password",
+			err:      providerutils.ErrInvalidVault,
+		},
+		{
+			name:     "not hex",
+			vault:    []byte(strings.Join(append(lines[:2:2], "zz"+lines[2][2:]), "\n")),
+			password: "password",
+			err:      providerutils.ErrInvalidVault,
+		},
+		{
+			name:     "padding bytes differ",
+			vault:    sealVault(t, []byte("key: value\x01\x02\x03\x04\x05\x03"), "password"),
+			password: "password",
+			err:      providerutils.ErrInvalidVault,
+		},
+		{
+			name:     "zero padding",
+			vault:    sealVault(t, []byte("key: value\x00\x00\x00\x00\x00\x00"), "password"),
+			password: "password",
+			err:      providerutils.ErrInvalidVault,
+		},
+		{
+			name:     "padding longer than a block",
+			vault:    sealVault(t, bytes.Repeat([]byte{17}, 32), "password"),
+			password: "password",
+			err:      providerutils.ErrInvalidVault,
+		},
+		{
+			name:     "empty plaintext",
+			vault:    sealVault(t, nil, "password"),
+			password: "password",
+			err:      providerutils.ErrInvalidVault,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := providerutils.DecryptVault(test.vault, []byte(test.password))
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestDecryptVaultValidPadding(t *testing.T) {
+	t.Parallel()
+
+	plaintext, err := providerutils.DecryptVault(sealVault(t, []byte("key: value\x06\x06\x06\x06\x06\x06"), "password"),
+		[]byte("password"))
+	require.NoError(t, err)
+	assert.Equal(t, "key: value", string(plaintext))
+}